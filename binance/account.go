@@ -1,124 +1,329 @@
 package binance
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"math"
-	"sort"
+	"slices"
 	"strconv"
+	"time"
 )
 
 // AccountBalance represents an asset in the user's Binance account
 type AccountBalance struct {
-	Symbol       string  // e.g., BTCUSDT, ETHUSDT
+	Symbol       string  // e.g., BTCUSDT, ETHUSDT; empty when the asset has no reporting currency market
 	Asset        string  // e.g., BTC, ETH
 	Free         float64 // available amount
 	Locked       float64 // in open orders
-	Total        float64 // Free + Locked
-	AveragePrice float64 // average buy price computed from trade history
+	Earn         float64 // in Simple Earn products; valued but not available to sell
+	Total        float64 // Free + Locked + Earn
+	AveragePrice float64 // average buy price computed from trade history, fees included
 	CostPrice    float64
 	TotalUSDT    float64 // Total * AveragePrice
+	Commission   float64 // total fees paid on this asset, in USDT
+	LedgerQty    float64 // quantity held according to trades, transfers and conversions
+	RealizedPnL  float64 // PnL of the sales so far, in USDT
+	Sales        []Sale  // every sale with its realized PnL
+	OpenLots     []Lot   // lots still held, as consumed by LotMethod
 }
 
-// GetAccountBalances fetches balances and computes AveragePrice for each symbol (e.g., BTCUSDT)
+// qtyMismatchTolerance is the share of the holding the ledger may be off by
+// (rounding, untracked rewards) before it is flagged
+const qtyMismatchTolerance = 0.01
+
+// QtyMismatch reports whether the ledger quantity and the actual holding
+// disagree by more than the tolerance, i.e. AveragePrice misses some history
+func (a AccountBalance) QtyMismatch() bool {
+	return math.Abs(a.Total-a.LedgerQty) > a.Total*qtyMismatchTolerance
+}
+
+// GetAccountBalances fetches balances and computes AveragePrice for each asset,
+// merging its trades on every market in QuoteAssets (e.g., BTCUSDT, BTCFDUSD)
 func (b *HttpRequest) GetAccountBalances() ([]AccountBalance, error) {
-	body, err := b.SignedRequest("GET", "/api/v3/account", nil)
+	return b.GetAccountBalancesContext(context.Background())
+}
+
+// GetAccountBalancesContext is GetAccountBalances with a context
+func (b *HttpRequest) GetAccountBalancesContext(ctx context.Context) ([]AccountBalance, error) {
+	body, err := b.SignedRequestContext(ctx, "GET", "/api/v3/account", nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch account balances: %w", err)
 	}
 
+	type rawBalance struct {
+		Asset  string `json:"asset"`
+		Free   string `json:"free"`
+		Locked string `json:"locked"`
+	}
 	var result struct {
-		Balances []struct {
-			Asset  string `json:"asset"`
-			Free   string `json:"free"`
-			Locked string `json:"locked"`
-		} `json:"balances"`
+		Balances []rawBalance `json:"balances"`
 	}
 	if err := json.Unmarshal(body, &result); err != nil {
 		return nil, fmt.Errorf("failed to parse account balances: %w", err)
 	}
 
+	// coins parked in Simple Earn are not part of the spot balances
+	earn, err := b.GetEarnPositionsContext(ctx)
+	if err != nil {
+		fmt.Printf("⚠️  Simple Earn positions unavailable: %v\n", err)
+	}
+	spot := make(map[string]bool, len(result.Balances))
+	for _, bItem := range result.Balances {
+		spot[bItem.Asset] = true
+	}
+	for asset := range earn {
+		if !spot[asset] {
+			result.Balances = append(result.Balances, rawBalance{Asset: asset})
+		}
+	}
+
+	// markets whose trades are quote-side legs of the quote assets
+	held := make([]string, 0, len(result.Balances))
+	for _, bItem := range result.Balances {
+		free, _ := strconv.ParseFloat(bItem.Free, 64)
+		locked, _ := strconv.ParseFloat(bItem.Locked, 64)
+		if free+locked+earn[bItem.Asset] > 0 {
+			held = append(held, bItem.Asset)
+		}
+	}
+	b.accountMu.Lock()
+	b.accountAssets = held
+	for _, asset := range held {
+		b.seenAssets[asset] = true
+	}
+	b.accountMu.Unlock()
+
 	var balances []AccountBalance
 	for _, bItem := range result.Balances {
 		free, _ := strconv.ParseFloat(bItem.Free, 64)
 		locked, _ := strconv.ParseFloat(bItem.Locked, 64)
-		total := free + locked
+		total := free + locked + earn[bItem.Asset]
 
-		// Skip empty / stablecoin-only entries
-		if total <= 0.01 || bItem.Asset == "USDT" {
+		// Skip empty / reporting currency entries
+		if total <= 0.01 || bItem.Asset == b.ReportingAsset {
 			continue
 		}
 
-		symbol := bItem.Asset + "USDT"
+		// Trade and value the asset on its reporting currency market, if there is one
+		symbol := bItem.Asset + b.ReportingAsset
+		if ok, err := b.symbolExists(ctx, symbol); err != nil {
+			fmt.Printf("⚠️  %s: market lookup failed, skipping it this cycle: %v\n", bItem.Asset, err)
+			symbol = ""
+		} else if !ok {
+			fmt.Printf("⚠️  %s: no %s market, holding can't be priced or traded\n", bItem.Asset, b.ReportingAsset)
+			symbol = ""
+		}
 
-		// Compute average buy price from trade history (FIFO)
-		averagePrice, costPrice, err := b.computeAverageAveragePrice(symbol)
+		// Compute average buy price and open lots from trade history
+		basis, err := b.computeAverageAveragePrice(ctx, bItem.Asset)
 		if err != nil {
-			fmt.Printf("⚠️  %s: cannot compute buy price: %v\n", symbol, err)
+			fmt.Printf("⚠️  %s: cannot compute buy price: %v\n", bItem.Asset, err)
 		}
 
-		balances = append(balances, AccountBalance{
+		balance := AccountBalance{
 			Symbol:       symbol,
 			Asset:        bItem.Asset,
 			Free:         free,
 			Locked:       locked,
+			Earn:         earn[bItem.Asset],
 			Total:        total,
-			AveragePrice: averagePrice,
-			CostPrice:    costPrice,
-			TotalUSDT:    total * averagePrice,
-		})
+			AveragePrice: basis.averagePrice,
+			CostPrice:    basis.costPrice,
+			TotalUSDT:    total * basis.averagePrice,
+			Commission:   basis.commission,
+			LedgerQty:    basis.quantity,
+			RealizedPnL:  basis.realizedPnL,
+			Sales:        basis.sales,
+			OpenLots:     basis.openLots,
+		}
+		if balance.QtyMismatch() {
+			fmt.Printf("⚠️  %s: holding %.8f but the trade ledger accounts for %.8f\n", bItem.Asset, total, basis.quantity)
+		}
+		balances = append(balances, balance)
 	}
 
 	return balances, nil
 }
 
-// computeAverageAveragePrice returns both average buy price and cost price (after sells)
-func (b *HttpRequest) computeAverageAveragePrice(symbol string) (averagePrice, costPrice float64, err error) {
-	trades, err := b.GetTradeHistory(symbol, 500)
+// assetTrades merges the trades of asset on every configured quote market,
+// its quote-side legs when it is a quote asset itself, its deposits and
+// withdrawals and its Convert and dust conversions, with prices and
+// commissions converted into the reporting currency
+func (b *HttpRequest) assetTrades(ctx context.Context, asset string) ([]Trade, error) {
+	var all []Trade
+	for _, quote := range b.QuoteAssets {
+		if quote == asset {
+			continue
+		}
+		symbol := asset + quote
+		if ok, err := b.symbolExists(ctx, symbol); err != nil {
+			return nil, err
+		} else if !ok {
+			continue
+		}
+
+		trades, err := b.GetAllTradesContext(ctx, symbol)
+		if err != nil {
+			return nil, err
+		}
+		b.applyCommissions(ctx, trades, asset, quote)
+		if err := b.convertTrades(ctx, trades, quote); err != nil {
+			return nil, fmt.Errorf("%s: %w", symbol, err)
+		}
+		all = append(all, trades...)
+	}
+
+	// spending or receiving asset as the quote of another market, e.g. BTC on ETHBTC
+	if slices.Contains(b.QuoteAssets, asset) && asset != b.ReportingAsset {
+		legs, err := b.quoteTrades(ctx, asset)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, legs...)
+	}
+
+	// coins moved in or out of the wallet
+	transfers, err := b.transferTrades(ctx, asset)
 	if err != nil {
-		return 0, 0, err
-	}
-	if len(trades) == 0 {
-		return 0, 0, fmt.Errorf("no trade history")
-	}
-
-	// Ensure chronological order (FIFO)
-	sort.Slice(trades, func(i, j int) bool {
-		return trades[i].Time.Before(trades[j].Time)
-	})
-
-	// Weighted average (for AveragePrice) and running balance (for CostPrice)
-	var totalBuyQty, totalBuyValue float64
-	var currentQty, currentCost float64
-
-	for _, t := range trades {
-		if t.IsBuyer {
-			totalBuyQty += t.Qty
-			totalBuyValue += t.Price * t.Qty
-
-			// FIFO-based remaining position
-			currentCost += t.Price * t.Qty
-			currentQty += t.Qty
-		} else {
-			// Sell — reduce from position cost
-			if currentQty > 0 {
-				avgCost := currentCost / currentQty
-				reduce := math.Min(t.Qty, currentQty)
-				currentCost -= avgCost * reduce
-				currentQty -= reduce
+		fmt.Printf("⚠️  %s: wallet history unavailable: %v\n", asset, err)
+	}
+	all = append(all, transfers...)
+
+	// Convert and dust-to-BNB conversions never show up in myTrades
+	conversions, err := b.conversionTrades(ctx, asset)
+	if err != nil {
+		fmt.Printf("⚠️  %s: convert history unavailable: %v\n", asset, err)
+	}
+	all = append(all, conversions...)
+	return all, nil
+}
+
+// quoteTrades books the trades on quote's markets as the opposite leg for
+// quote: buying the base spends quote, selling it brings quote in. Fees
+// paid in quote are already part of the base asset's cost basis, so the legs
+// carry the net amount only. myTrades needs a symbol, so only the markets of
+// assets currently held are looked up.
+func (b *HttpRequest) quoteTrades(ctx context.Context, quote string) ([]Trade, error) {
+	b.accountMu.Lock()
+	bases := b.accountAssets
+	b.accountMu.Unlock()
+
+	var legs []Trade
+	for _, base := range bases {
+		if base == quote {
+			continue
+		}
+		symbol := base + quote
+		if ok, err := b.symbolExists(ctx, symbol); err != nil {
+			return nil, err
+		} else if !ok {
+			continue
+		}
+
+		trades, err := b.GetAllTradesContext(ctx, symbol)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range trades {
+			rate, err := b.conversionRate(ctx, quote, t.Time)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", symbol, err)
+			}
+			amount := t.Price * t.Qty
+			fee := 0.0
+			if t.CommissionAsset == quote {
+				fee = t.Commission
 			}
+			if t.IsBuyer {
+				amount += fee
+			} else {
+				amount -= fee
+			}
+			legs = append(legs, Trade{
+				ID:      t.ID,
+				Symbol:  symbol,
+				Price:   rate,
+				Qty:     amount,
+				IsBuyer: !t.IsBuyer,
+				Time:    t.Time,
+				Source:  TradeSourceSpot,
+			})
+		}
+	}
+	return legs, nil
+}
+
+// convertTrades rewrites Price and CommissionQuote from quote into the
+// reporting currency, using the conversion rate at each fill time
+func (b *HttpRequest) convertTrades(ctx context.Context, trades []Trade, quote string) error {
+	if quote == b.ReportingAsset {
+		return nil
+	}
+	for i := range trades {
+		rate, err := b.conversionRate(ctx, quote, trades[i].Time)
+		if err != nil {
+			return err
 		}
+		trades[i].Price *= rate
+		trades[i].CommissionQuote *= rate
+	}
+	return nil
+}
+
+// conversionRate returns how much of the reporting currency one unit of asset was worth at t
+func (b *HttpRequest) conversionRate(ctx context.Context, asset string, t time.Time) (float64, error) {
+	direct, err := b.symbolExists(ctx, asset+b.ReportingAsset)
+	if err != nil {
+		return 0, err
+	}
+	if direct {
+		return b.priceAt(ctx, asset+b.ReportingAsset, t)
 	}
 
-	if totalBuyQty == 0 {
-		return 0, 0, fmt.Errorf("no BUY trades found")
+	// only the inverse market exists, e.g. USDTBRL
+	price, err := b.priceAt(ctx, b.ReportingAsset+asset, t)
+	if err != nil {
+		return 0, fmt.Errorf("cannot convert %s to %s: %w", asset, b.ReportingAsset, err)
 	}
-	averagePrice = totalBuyValue / totalBuyQty
+	return 1 / price, nil
+}
+
+// costBasis is what the trade ledger of an asset adds up to
+type costBasis struct {
+	averagePrice float64 // average buy price, fees included
+	costPrice    float64 // average cost of the open lots
+	commission   float64 // total fees paid
+	quantity     float64 // quantity held according to the ledger
+	realizedPnL  float64 // PnL of every sale so far
+	sales        []Sale
+	openLots     []Lot
+}
 
-	if currentQty == 0 {
-		return averagePrice, 0, fmt.Errorf("no holdings left — all sold")
+// computeAverageAveragePrice returns both average buy price and cost price of
+// the open lots (consumed by LotMethod on sells), with commissions included,
+// plus the total commission paid, the realized PnL and the quantity the ledger
+// says is held, all in the reporting currency
+func (b *HttpRequest) computeAverageAveragePrice(ctx context.Context, asset string) (basis costBasis, err error) {
+	ledger, err := b.GetLotLedgerContext(ctx, asset)
+	if err != nil {
+		return basis, err
+	}
+	basis = costBasis{
+		averagePrice: ledger.AveragePrice(),
+		costPrice:    ledger.CostPrice(),
+		commission:   ledger.Commission,
+		quantity:     ledger.Quantity(),
+		realizedPnL:  ledger.RealizedPnL(),
+		sales:        ledger.Sales,
+		openLots:     ledger.OpenLots,
 	}
-	costPrice = currentCost / currentQty
 
-	return averagePrice, costPrice, nil
+	if basis.averagePrice <= 0 {
+		return basis, fmt.Errorf("no BUY trades found")
+	}
+	if basis.quantity <= 0 {
+		return basis, fmt.Errorf("no holdings left — all sold")
+	}
+	return basis, nil
 }
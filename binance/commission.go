@@ -0,0 +1,62 @@
+package binance
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"time"
+)
+
+// applyCommissions fills CommissionQuote on each trade: the commission
+// converted into the quote currency using the price at fill time.
+func (b *HttpRequest) applyCommissions(ctx context.Context, trades []Trade, baseAsset, quoteAsset string) {
+	for i := range trades {
+		t := &trades[i]
+		switch t.CommissionAsset {
+		case "", quoteAsset:
+			t.CommissionQuote = t.Commission
+		case baseAsset:
+			t.CommissionQuote = t.Commission * t.Price
+		default:
+			// e.g. BNB fee discount: value it at the BNB/quote price of that minute
+			price, err := b.priceAt(ctx, t.CommissionAsset+quoteAsset, t.Time)
+			if err != nil {
+				log.Printf("⚠️  %s: cannot value %s commission of trade %d: %v\n", t.Symbol, t.CommissionAsset, t.ID, err)
+				continue
+			}
+			t.CommissionQuote = t.Commission * price
+		}
+	}
+}
+
+// priceAt returns the close of the 1m candle containing t; results are cached
+func (b *HttpRequest) priceAt(ctx context.Context, symbol string, t time.Time) (float64, error) {
+	minute := t.Truncate(time.Minute)
+	key := symbol + "@" + strconv.FormatInt(minute.Unix(), 10)
+
+	b.pricesAtMu.Lock()
+	price, ok := b.pricesAt[key]
+	b.pricesAtMu.Unlock()
+	if ok {
+		return price, nil
+	}
+
+	klines, err := b.fetchKlines(ctx, map[string]string{
+		"symbol":    symbol,
+		"interval":  "1m",
+		"startTime": strconv.FormatInt(minute.UnixMilli(), 10),
+		"limit":     "1",
+	})
+	if err != nil {
+		return 0, err
+	}
+	if len(klines) == 0 {
+		return 0, ErrNoKlines
+	}
+	price = klines[0].Close
+
+	b.pricesAtMu.Lock()
+	b.pricesAt[key] = price
+	b.pricesAtMu.Unlock()
+	return price, nil
+}
@@ -0,0 +1,245 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+const convertWindow = 30 * 24 * time.Hour // longest range /sapi/v1/convert/tradeFlow accepts
+
+// Conversion is a Binance Convert trade or a dust-to-BNB conversion
+type Conversion struct {
+	ID         string
+	FromAsset  string
+	FromAmount float64
+	ToAsset    string
+	ToAmount   float64 // amount credited, after Fee
+	Fee        float64 // dust service charge, in ToAsset
+	Time       time.Time
+	Dust       bool
+}
+
+// GetConversions returns the Convert trades since TransferHistoryStart and
+// the recent dust conversions. The history is cached and refreshed incrementally.
+func (b *HttpRequest) GetConversions() ([]Conversion, error) {
+	return b.GetConversionsContext(context.Background())
+}
+
+// GetConversionsContext is GetConversions with a context
+func (b *HttpRequest) GetConversionsContext(ctx context.Context) ([]Conversion, error) {
+	b.conversionsMu.Lock()
+	defer b.conversionsMu.Unlock()
+
+	now := time.Now()
+	if time.Since(b.conversionsAt) < transferRefreshInterval {
+		return b.conversionList(), nil
+	}
+
+	from := b.TransferHistoryStart
+	if !b.conversionsAt.IsZero() {
+		from = b.conversionsAt.Add(-transferPendingWindow)
+	}
+	for start := from; start.Before(now); start = start.Add(convertWindow) {
+		end := start.Add(convertWindow)
+		if end.After(now) {
+			end = now
+		}
+		converts, err := b.fetchConvertTrades(ctx, start, end)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range converts {
+			b.conversions["C"+c.ID] = c
+		}
+	}
+
+	dust, err := b.fetchDustLog(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range dust {
+		b.conversions["D"+c.ID+c.FromAsset] = c
+	}
+
+	b.conversionsAt = now
+	return b.conversionList(), nil
+}
+
+func (b *HttpRequest) conversionList() []Conversion {
+	list := make([]Conversion, 0, len(b.conversions))
+	for _, c := range b.conversions {
+		list = append(list, c)
+	}
+	return list
+}
+
+// fetchConvertTrades reads /sapi/v1/convert/tradeFlow for [start, end),
+// splitting the range when it holds more than one page
+func (b *HttpRequest) fetchConvertTrades(ctx context.Context, start, end time.Time) ([]Conversion, error) {
+	body, err := b.SignedRequestContext(ctx, "GET", "/sapi/v1/convert/tradeFlow", map[string]string{
+		"startTime": strconv.FormatInt(start.UnixMilli(), 10),
+		"endTime":   strconv.FormatInt(end.UnixMilli()-1, 10),
+		"limit":     strconv.Itoa(transferPageSize),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch convert history: %w", err)
+	}
+
+	var raw struct {
+		List []struct {
+			OrderID     int64  `json:"orderId"`
+			OrderStatus string `json:"orderStatus"`
+			FromAsset   string `json:"fromAsset"`
+			FromAmount  string `json:"fromAmount"`
+			ToAsset     string `json:"toAsset"`
+			ToAmount    string `json:"toAmount"`
+			CreateTime  int64  `json:"createTime"`
+		} `json:"list"`
+		MoreData bool `json:"moreData"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse convert history: %w", err)
+	}
+
+	if raw.MoreData && end.Sub(start) > time.Minute {
+		mid := start.Add(end.Sub(start) / 2)
+		first, err := b.fetchConvertTrades(ctx, start, mid)
+		if err != nil {
+			return nil, err
+		}
+		second, err := b.fetchConvertTrades(ctx, mid, end)
+		if err != nil {
+			return nil, err
+		}
+		return append(first, second...), nil
+	}
+
+	var conversions []Conversion
+	for _, c := range raw.List {
+		if c.OrderStatus != "SUCCESS" {
+			continue
+		}
+		conversion := Conversion{
+			ID:        strconv.FormatInt(c.OrderID, 10),
+			FromAsset: c.FromAsset,
+			ToAsset:   c.ToAsset,
+			Time:      time.UnixMilli(c.CreateTime),
+		}
+		conversion.FromAmount, _ = strconv.ParseFloat(c.FromAmount, 64)
+		conversion.ToAmount, _ = strconv.ParseFloat(c.ToAmount, 64)
+		conversions = append(conversions, conversion)
+	}
+	return conversions, nil
+}
+
+// fetchDustLog reads the dust-to-BNB conversions from /sapi/v1/asset/dribblet.
+// Binance only returns the latest 100 conversions.
+func (b *HttpRequest) fetchDustLog(ctx context.Context) ([]Conversion, error) {
+	body, err := b.SignedRequestContext(ctx, "GET", "/sapi/v1/asset/dribblet", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch dust log: %w", err)
+	}
+
+	var raw struct {
+		UserAssetDribblets []struct {
+			TransID int64 `json:"transId"`
+			Details []struct {
+				FromAsset           string `json:"fromAsset"`
+				Amount              string `json:"amount"`
+				TransferedAmount    string `json:"transferedAmount"`
+				ServiceChargeAmount string `json:"serviceChargeAmount"`
+				OperateTime         int64  `json:"operateTime"`
+			} `json:"userAssetDribbletDetails"`
+		} `json:"userAssetDribblets"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse dust log: %w", err)
+	}
+
+	var conversions []Conversion
+	for _, d := range raw.UserAssetDribblets {
+		for _, detail := range d.Details {
+			conversion := Conversion{
+				ID:        strconv.FormatInt(d.TransID, 10),
+				FromAsset: detail.FromAsset,
+				ToAsset:   "BNB",
+				Time:      time.UnixMilli(detail.OperateTime),
+				Dust:      true,
+			}
+			conversion.FromAmount, _ = strconv.ParseFloat(detail.Amount, 64)
+			conversion.ToAmount, _ = strconv.ParseFloat(detail.TransferedAmount, 64)
+			conversion.Fee, _ = strconv.ParseFloat(detail.ServiceChargeAmount, 64)
+			conversions = append(conversions, conversion)
+		}
+	}
+	return conversions, nil
+}
+
+// conversionTrades turns the Convert and dust conversions touching asset
+// into synthetic trades, valued in the reporting currency at conversion time:
+// a buy when asset was received and a sell when it was given away
+func (b *HttpRequest) conversionTrades(ctx context.Context, asset string) ([]Trade, error) {
+	conversions, err := b.GetConversionsContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var trades []Trade
+	for _, c := range conversions {
+		if c.FromAsset != asset && c.ToAsset != asset {
+			continue
+		}
+
+		value, err := b.conversionValue(ctx, c)
+		if err != nil {
+			fmt.Printf("⚠️  %s: conversion %s ignored for cost basis: %v\n", asset, c.ID, err)
+			continue
+		}
+
+		source := TradeSourceConvert
+		if c.Dust {
+			source = TradeSourceDust
+		}
+		trade := Trade{
+			Symbol: asset + b.ReportingAsset,
+			Time:   c.Time,
+			Source: source,
+		}
+		if c.ToAsset == asset {
+			trade.IsBuyer = true
+			trade.Qty = c.ToAmount
+		} else {
+			trade.Qty = c.FromAmount
+		}
+		if trade.Qty <= 0 {
+			continue
+		}
+		trade.Price = value / trade.Qty
+		trades = append(trades, trade)
+	}
+	return trades, nil
+}
+
+// conversionValue returns what a conversion was worth in the reporting currency
+func (b *HttpRequest) conversionValue(ctx context.Context, c Conversion) (float64, error) {
+	switch b.ReportingAsset {
+	case c.FromAsset:
+		return c.FromAmount, nil
+	case c.ToAsset:
+		return c.ToAmount, nil
+	}
+
+	// value what was received; dust has no market of its own
+	rate, err := b.conversionRate(ctx, c.ToAsset, c.Time)
+	if err == nil {
+		return c.ToAmount * rate, nil
+	}
+	rate, fromErr := b.conversionRate(ctx, c.FromAsset, c.Time)
+	if fromErr != nil {
+		return 0, err
+	}
+	return c.FromAmount * rate, nil
+}
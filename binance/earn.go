@@ -0,0 +1,64 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+const earnPageSize = 100
+
+// GetEarnPositions returns the amount held in Simple Earn flexible and
+// locked products, by asset
+func (b *HttpRequest) GetEarnPositions() (map[string]float64, error) {
+	return b.GetEarnPositionsContext(context.Background())
+}
+
+// GetEarnPositionsContext is GetEarnPositions with a context
+func (b *HttpRequest) GetEarnPositionsContext(ctx context.Context) (map[string]float64, error) {
+	positions := make(map[string]float64)
+	if err := b.fetchEarnPositions(ctx, "/sapi/v1/simple-earn/flexible/position", positions); err != nil {
+		return nil, fmt.Errorf("failed to fetch flexible earn positions: %w", err)
+	}
+	if err := b.fetchEarnPositions(ctx, "/sapi/v1/simple-earn/locked/position", positions); err != nil {
+		return nil, fmt.Errorf("failed to fetch locked earn positions: %w", err)
+	}
+	return positions, nil
+}
+
+// fetchEarnPositions pages through a Simple Earn position endpoint and adds
+// the amount of each position to positions
+func (b *HttpRequest) fetchEarnPositions(ctx context.Context, endpoint string, positions map[string]float64) error {
+	for page := 1; ; page++ {
+		body, err := b.SignedRequestContext(ctx, "GET", endpoint, map[string]string{
+			"current": strconv.Itoa(page),
+			"size":    strconv.Itoa(earnPageSize),
+		})
+		if err != nil {
+			return err
+		}
+
+		var result struct {
+			Rows []struct {
+				Asset       string `json:"asset"`
+				TotalAmount string `json:"totalAmount"` // flexible products
+				Amount      string `json:"amount"`      // locked products
+			} `json:"rows"`
+		}
+		if err := json.Unmarshal(body, &result); err != nil {
+			return fmt.Errorf("failed to parse earn positions: %w", err)
+		}
+		for _, row := range result.Rows {
+			amount := row.TotalAmount
+			if amount == "" {
+				amount = row.Amount
+			}
+			v, _ := strconv.ParseFloat(amount, 64)
+			positions[row.Asset] += v
+		}
+		if len(result.Rows) < earnPageSize {
+			return nil
+		}
+	}
+}
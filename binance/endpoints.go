@@ -0,0 +1,80 @@
+package binance
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+)
+
+// Endpoints is the set of hosts of one Binance environment
+type Endpoints struct {
+	REST       []string // REST base URLs, tried in order
+	MarketData []string // extra REST base URLs that only serve public market data
+	Stream     []string // websocket base URLs, tried in order
+}
+
+// MainnetEndpoints is the production spot environment with its api1-api4
+// mirrors and the data-api market data host as fallbacks
+var MainnetEndpoints = Endpoints{
+	REST: []string{
+		"https://api.binance.com",
+		"https://api1.binance.com",
+		"https://api2.binance.com",
+		"https://api3.binance.com",
+		"https://api4.binance.com",
+	},
+	MarketData: []string{"https://data-api.binance.vision"},
+	Stream:     []string{"wss://stream.binance.com:9443", "wss://stream.binance.com:443"},
+}
+
+// TestnetEndpoints is the spot testnet; it needs its own API keys
+var TestnetEndpoints = Endpoints{
+	REST:   []string{"https://testnet.binance.vision"},
+	Stream: []string{"wss://stream.testnet.binance.vision"},
+}
+
+// EndpointsFor returns the endpoints of a named profile: mainnet or testnet
+func EndpointsFor(profile string) (Endpoints, error) {
+	switch strings.ToLower(profile) {
+	case "", "mainnet":
+		return MainnetEndpoints, nil
+	case "testnet":
+		return TestnetEndpoints, nil
+	}
+	return Endpoints{}, fmt.Errorf("unknown Binance profile %q (mainnet, testnet)", profile)
+}
+
+// endpointPool hands out the current base URL of a list and moves on to the
+// next one when the current host fails
+type endpointPool struct {
+	mu      sync.Mutex
+	urls    []string
+	current int
+}
+
+func newEndpointPool(urls ...string) *endpointPool {
+	return &endpointPool{urls: urls}
+}
+
+func (p *endpointPool) get() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.urls[p.current]
+}
+
+func (p *endpointPool) size() int {
+	return len(p.urls)
+}
+
+// failover switches to the next URL, unless another request already moved
+// away from failed
+func (p *endpointPool) failover(failed string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.urls) < 2 || p.urls[p.current] != failed {
+		return
+	}
+	p.current = (p.current + 1) % len(p.urls)
+	log.Printf("🔀 %s unavailable, switching to %s\n", failed, p.urls[p.current])
+}
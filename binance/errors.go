@@ -0,0 +1,59 @@
+package binance
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// APIError is a Binance error response: {"code": -1121, "msg": "Invalid symbol."}
+type APIError struct {
+	StatusCode int    `json:"-"`
+	Code       int    `json:"code"`
+	Msg        string `json:"msg"`
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("Binance API error (%d): code=%d msg=%s", e.StatusCode, e.Code, e.Msg)
+}
+
+// Is reports whether target is an *APIError with the same Binance code,
+// so the sentinel values below work with errors.Is.
+func (e *APIError) Is(target error) bool {
+	t, ok := target.(*APIError)
+	return ok && t.Code != 0 && t.Code == e.Code
+}
+
+// Well-known Binance error codes
+var (
+	ErrTooManyRequests              = &APIError{Code: -1003, Msg: "too many requests"}
+	ErrTimestampOutsideRecvWindow   = &APIError{Code: -1021, Msg: "timestamp outside recvWindow"}
+	ErrInvalidSignature             = &APIError{Code: -1022, Msg: "invalid signature"}
+	ErrFilterFailure                = &APIError{Code: -1013, Msg: "filter failure"}
+	ErrInvalidSymbol                = &APIError{Code: -1121, Msg: "invalid symbol"}
+	ErrNewOrderRejected             = &APIError{Code: -2010, Msg: "new order rejected"}
+	ErrCancelRejected               = &APIError{Code: -2011, Msg: "cancel rejected"}
+	ErrNoSuchOrder                  = &APIError{Code: -2013, Msg: "order does not exist"}
+	ErrInvalidAPIKey                = &APIError{Code: -2015, Msg: "invalid API key, IP, or permissions"}
+	ErrCancelReplacePartiallyFailed = &APIError{Code: -2021, Msg: "order cancel-replace partially failed"}
+	ErrCancelReplaceFailed          = &APIError{Code: -2022, Msg: "order cancel-replace failed"}
+)
+
+// IsInsufficientBalance reports whether err is a new order rejected because
+// the account can't pay for it. -2010 also covers orders that would trigger
+// immediately, closed markets and account restrictions; only the message tells them apart.
+func IsInsufficientBalance(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Code == ErrNewOrderRejected.Code &&
+		strings.Contains(strings.ToLower(apiErr.Msg), "insufficient balance")
+}
+
+// newAPIError parses a non-200 response body into an *APIError
+func newAPIError(statusCode int, body []byte) *APIError {
+	apiErr := &APIError{StatusCode: statusCode}
+	if err := json.Unmarshal(body, apiErr); err != nil || apiErr.Msg == "" {
+		apiErr.Msg = string(body)
+	}
+	return apiErr
+}
@@ -0,0 +1,24 @@
+package binance
+
+import "context"
+
+// Exchange is the set of venue operations the trading loop depends on.
+// HttpRequest implements it against the Binance REST API; tests and other
+// venues can provide their own implementation.
+type Exchange interface {
+	GetAccountBalancesContext(ctx context.Context) ([]AccountBalance, error)
+	GetPricesContext(ctx context.Context, symbols []string) (map[string]float64, error)
+	GetKlinesContext(ctx context.Context, symbol, interval string, limit int) ([]Kline, error)
+	GetLotLedgerContext(ctx context.Context, asset string) (*LotLedger, error)
+	GetTradedAssetsContext(ctx context.Context) ([]string, error)
+	CreateOrderContext(ctx context.Context, req OrderRequest) (*OrderResult, error)
+	TestOrderContext(ctx context.Context, req OrderRequest) (*OrderCommission, error)
+	GetOpenOrdersContext(ctx context.Context, symbol string) ([]*OrderResult, error)
+	GetOrderByClientIDContext(ctx context.Context, symbol, clientOrderID string) (*OrderResult, error)
+	CancelOrderContext(ctx context.Context, symbol string, orderID int64) (*OrderResult, error)
+	PlaceOCOContext(ctx context.Context, req OCORequest) (*OrderList, error)
+	GetOrderListContext(ctx context.Context, orderListID int64) (*OrderList, error)
+	CancelOrderListContext(ctx context.Context, symbol string, orderListID int64) (*OrderList, error)
+}
+
+var _ Exchange = (*HttpRequest)(nil)
@@ -0,0 +1,220 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// SymbolFilters holds the exchangeInfo trading rules for a symbol
+type SymbolFilters struct {
+	Symbol      string
+	StepSize    float64 // LOT_SIZE step
+	MinQty      float64
+	MaxQty      float64
+	TickSize    float64 // PRICE_FILTER tick
+	MinPrice    float64
+	MaxPrice    float64
+	MinNotional float64 // MIN_NOTIONAL / NOTIONAL
+	MaxNotional float64
+
+	QuotePrecision int // decimals allowed for quoteOrderQty
+
+	qtyPrecision   int
+	pricePrecision int
+}
+
+// FilterError is returned when an order would violate a symbol filter
+type FilterError struct {
+	Symbol string
+	Filter string // LOT_SIZE, PRICE_FILTER, NOTIONAL
+	Reason string
+}
+
+func (e *FilterError) Error() string {
+	return fmt.Sprintf("%s %s filter: %s", e.Symbol, e.Filter, e.Reason)
+}
+
+// Unwrap lets errors.Is(err, ErrFilterFailure) match local and exchange-side rejections alike
+func (e *FilterError) Unwrap() error {
+	return ErrFilterFailure
+}
+
+// GetSymbolFilters returns the cached filters for symbol, loading exchangeInfo on first use
+func (b *HttpRequest) GetSymbolFilters(symbol string) (*SymbolFilters, error) {
+	return b.getSymbolFilters(context.Background(), symbol)
+}
+
+func (b *HttpRequest) getSymbolFilters(ctx context.Context, symbol string) (*SymbolFilters, error) {
+	b.filtersMu.Lock()
+	f, ok := b.filters[symbol]
+	b.filtersMu.Unlock()
+	if ok {
+		if f == nil {
+			return nil, ErrInvalidSymbol
+		}
+		return f, nil
+	}
+
+	body, err := b.PublicRequestContext(ctx, "/api/v3/exchangeInfo", map[string]string{"symbol": symbol})
+	if errors.Is(err, ErrInvalidSymbol) {
+		// remember markets that don't exist so we don't ask again
+		b.filtersMu.Lock()
+		b.filters[symbol] = nil
+		b.filtersMu.Unlock()
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch exchange info: %w", err)
+	}
+
+	var result struct {
+		Symbols []struct {
+			Symbol              string            `json:"symbol"`
+			QuoteAssetPrecision int               `json:"quoteAssetPrecision"`
+			Filters             []json.RawMessage `json:"filters"`
+		} `json:"symbols"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse exchange info: %w", err)
+	}
+	if len(result.Symbols) == 0 {
+		return nil, fmt.Errorf("no exchange info for %s", symbol)
+	}
+
+	f, err = parseSymbolFilters(result.Symbols[0].Symbol, result.Symbols[0].Filters)
+	if err != nil {
+		return nil, err
+	}
+	f.QuotePrecision = result.Symbols[0].QuoteAssetPrecision
+
+	b.filtersMu.Lock()
+	b.filters[symbol] = f
+	b.filtersMu.Unlock()
+	return f, nil
+}
+
+// symbolExists reports whether symbol is a market on the exchange
+func (b *HttpRequest) symbolExists(ctx context.Context, symbol string) (bool, error) {
+	_, err := b.getSymbolFilters(ctx, symbol)
+	if errors.Is(err, ErrInvalidSymbol) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// forgetSymbol drops the cached filters of symbol, so the next lookup asks
+// exchangeInfo again whether the market still exists
+func (b *HttpRequest) forgetSymbol(symbol string) {
+	b.filtersMu.Lock()
+	delete(b.filters, symbol)
+	b.filtersMu.Unlock()
+}
+
+func parseSymbolFilters(symbol string, raw []json.RawMessage) (*SymbolFilters, error) {
+	f := &SymbolFilters{Symbol: symbol}
+	for _, r := range raw {
+		var filter struct {
+			FilterType  string `json:"filterType"`
+			StepSize    string `json:"stepSize"`
+			MinQty      string `json:"minQty"`
+			MaxQty      string `json:"maxQty"`
+			TickSize    string `json:"tickSize"`
+			MinPrice    string `json:"minPrice"`
+			MaxPrice    string `json:"maxPrice"`
+			MinNotional string `json:"minNotional"`
+			MaxNotional string `json:"maxNotional"`
+		}
+		if err := json.Unmarshal(r, &filter); err != nil {
+			return nil, fmt.Errorf("failed to parse filter for %s: %w", symbol, err)
+		}
+
+		switch filter.FilterType {
+		case "LOT_SIZE":
+			f.StepSize, _ = strconv.ParseFloat(filter.StepSize, 64)
+			f.MinQty, _ = strconv.ParseFloat(filter.MinQty, 64)
+			f.MaxQty, _ = strconv.ParseFloat(filter.MaxQty, 64)
+			f.qtyPrecision = decimals(filter.StepSize)
+		case "PRICE_FILTER":
+			f.TickSize, _ = strconv.ParseFloat(filter.TickSize, 64)
+			f.MinPrice, _ = strconv.ParseFloat(filter.MinPrice, 64)
+			f.MaxPrice, _ = strconv.ParseFloat(filter.MaxPrice, 64)
+			f.pricePrecision = decimals(filter.TickSize)
+		case "MIN_NOTIONAL", "NOTIONAL":
+			f.MinNotional, _ = strconv.ParseFloat(filter.MinNotional, 64)
+			f.MaxNotional, _ = strconv.ParseFloat(filter.MaxNotional, 64)
+		}
+	}
+	return f, nil
+}
+
+// decimals returns the number of significant decimal places in a step like "0.01000000"
+func decimals(step string) int {
+	i := strings.IndexByte(step, '.')
+	if i < 0 {
+		return 0
+	}
+	return len(strings.TrimRight(step[i+1:], "0"))
+}
+
+// roundDown truncates v to a multiple of step
+func roundDown(v, step float64) float64 {
+	if step <= 0 {
+		return v
+	}
+	// small epsilon guards against 0.3/0.1 = 2.9999999
+	return math.Floor(v/step+1e-9) * step
+}
+
+// RoundQuantity rounds qty down to the LOT_SIZE step
+func (f *SymbolFilters) RoundQuantity(qty float64) float64 {
+	return roundDown(qty, f.StepSize)
+}
+
+// RoundPrice rounds price down to the PRICE_FILTER tick
+func (f *SymbolFilters) RoundPrice(price float64) float64 {
+	return roundDown(price, f.TickSize)
+}
+
+// FormatQuantity formats qty with the precision allowed by stepSize
+func (f *SymbolFilters) FormatQuantity(qty float64) string {
+	return strconv.FormatFloat(f.RoundQuantity(qty), 'f', f.qtyPrecision, 64)
+}
+
+// FormatPrice formats price with the precision allowed by tickSize
+func (f *SymbolFilters) FormatPrice(price float64) string {
+	return strconv.FormatFloat(f.RoundPrice(price), 'f', f.pricePrecision, 64)
+}
+
+// FormatQuoteQty formats a quote amount with the precision allowed for quoteOrderQty
+func (f *SymbolFilters) FormatQuoteQty(amount float64) string {
+	return strconv.FormatFloat(roundDown(amount, math.Pow10(-f.QuotePrecision)), 'f', f.QuotePrecision, 64)
+}
+
+// Validate checks an already rounded qty and price against the symbol filters
+func (f *SymbolFilters) Validate(qty, price float64) error {
+	if qty <= 0 || qty < f.MinQty {
+		return &FilterError{f.Symbol, "LOT_SIZE", fmt.Sprintf("quantity %g below minQty %g", qty, f.MinQty)}
+	}
+	if f.MaxQty > 0 && qty > f.MaxQty {
+		return &FilterError{f.Symbol, "LOT_SIZE", fmt.Sprintf("quantity %g above maxQty %g", qty, f.MaxQty)}
+	}
+	if price > 0 && price < f.MinPrice {
+		return &FilterError{f.Symbol, "PRICE_FILTER", fmt.Sprintf("price %g below minPrice %g", price, f.MinPrice)}
+	}
+	if price > 0 && f.MaxPrice > 0 && price > f.MaxPrice {
+		return &FilterError{f.Symbol, "PRICE_FILTER", fmt.Sprintf("price %g above maxPrice %g", price, f.MaxPrice)}
+	}
+	notional := qty * price
+	if notional < f.MinNotional {
+		return &FilterError{f.Symbol, "NOTIONAL", fmt.Sprintf("order value %.8f below min notional %g", notional, f.MinNotional)}
+	}
+	if f.MaxNotional > 0 && notional > f.MaxNotional {
+		return &FilterError{f.Symbol, "NOTIONAL", fmt.Sprintf("order value %.8f above max notional %g", notional, f.MaxNotional)}
+	}
+	return nil
+}
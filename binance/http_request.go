@@ -1,98 +1,285 @@
 package binance
 
 import (
-	"crypto/hmac"
-	"crypto/sha256"
-	"encoding/hex"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 )
 
 // HttpRequest is a helper for signed Binance API requests
 type HttpRequest struct {
-	APIKey    string
-	SecretKey string
-	BaseURL   string
-	Client    *http.Client
+	APIKey string
+	Signer Signer // signs the query of signed requests
+	Client *http.Client
+
+	QuoteAssets    []string // quote currencies whose markets count towards cost basis
+	ReportingAsset string   // currency AveragePrice and PnL are reported in
+
+	DepositCostBasis     string             // CostBasisMarket or CostBasisManual
+	ManualCostBasis      map[string]float64 // cost per unit of deposited coins by asset, in ReportingAsset
+	TransferHistoryStart time.Time          // deposits and withdrawals before this are ignored
+	PnLAssets            []string           // extra assets to report realized PnL for, e.g. sold off before startup
+	LotMethod            string             // LotMethodFIFO, LotMethodLIFO or LotMethodHIFO
+
+	RecvWindow       time.Duration // recvWindow sent with signed requests; 0 uses the Binance default (5s)
+	TimeSyncInterval time.Duration // how often to re-sync with server time; 0 disables syncing
+
+	limiter *rateLimiter
+
+	endpoints Endpoints
+	rest      *endpointPool // hosts for every request
+	public    *endpointPool // rest plus the market data only hosts
+
+	timeMu       sync.Mutex
+	timeOffset   time.Duration // server time - local time
+	lastTimeSync time.Time
+
+	filtersMu sync.Mutex
+	filters   map[string]*SymbolFilters // exchangeInfo cache by symbol
+
+	tradesMu sync.Mutex
+	trades   map[string][]Trade // full trade history cache by symbol
+
+	pricesAtMu sync.Mutex
+	pricesAt   map[string]float64 // historical 1m close by symbol@minute
+
+	accountMu     sync.Mutex
+	accountAssets []string        // assets with a balance in the latest account snapshot
+	seenAssets    map[string]bool // assets with a balance in any snapshot since startup
+
+	transfersMu sync.Mutex
+	transfers   map[string]Transfer // deposit and withdrawal cache by D<id> / W<id>
+	transfersAt time.Time           // when the cache was last refreshed
+
+	conversionsMu sync.Mutex
+	conversions   map[string]Conversion // Convert and dust cache by C<id> / D<id><asset>
+	conversionsAt time.Time
 }
 
-// NewHttpRequest creates a new Binance HttpRequest helper
+// NewHttpRequest creates a new Binance HttpRequest helper that signs with
+// an HMAC secret key; replace Signer to use an RSA or Ed25519 key instead
 func NewHttpRequest(apiKey, secretKey string) *HttpRequest {
-	return &HttpRequest{
-		APIKey:    apiKey,
-		SecretKey: secretKey,
-		BaseURL:   "https://api.binance.com",
-		Client:    &http.Client{Timeout: 10 * time.Second},
+	b := &HttpRequest{
+		APIKey: apiKey,
+		Signer: NewHMACSigner(secretKey),
+		Client: &http.Client{Timeout: 10 * time.Second},
+
+		QuoteAssets:    []string{"USDT"},
+		ReportingAsset: "USDT",
+
+		DepositCostBasis:     CostBasisMarket,
+		TransferHistoryStart: time.Now().Add(-defaultTransferLookback),
+		LotMethod:            LotMethodFIFO,
+
+		TimeSyncInterval: defaultTimeSyncInterval,
+
+		limiter: newRateLimiter(),
+		filters: make(map[string]*SymbolFilters),
+		trades:  make(map[string][]Trade),
+
+		pricesAt:  make(map[string]float64),
+		transfers: make(map[string]Transfer),
+
+		seenAssets: make(map[string]bool),
+
+		conversions: make(map[string]Conversion),
 	}
+	b.SetEndpoints(MainnetEndpoints)
+	return b
+}
+
+// SetEndpoints points the client at another environment or set of hosts
+func (b *HttpRequest) SetEndpoints(e Endpoints) {
+	b.endpoints = e
+	b.rest = newEndpointPool(e.REST...)
+	b.public = newEndpointPool(append(slices.Clone(e.REST), e.MarketData...)...)
+}
+
+// Endpoints returns the hosts the client talks to
+func (b *HttpRequest) Endpoints() Endpoints {
+	return b.endpoints
 }
 
-// signPayload generates the HMAC SHA256 signature
-func (b *HttpRequest) signPayload(query string) string {
-	mac := hmac.New(sha256.New, []byte(b.SecretKey))
-	mac.Write([]byte(query))
-	return hex.EncodeToString(mac.Sum(nil))
+// maxRetries is how many times a request is retried after a 429
+const maxRetries = 3
+
+// errNotSent marks failures that happened before a request left the client
+var errNotSent = errors.New("request not sent")
+
+// RateLimitUsage returns the latest request weight and order counts reported by Binance
+func (b *HttpRequest) RateLimitUsage() RateLimitUsage {
+	return b.limiter.usage()
+}
+
+// do sends the request built by build, waiting until weight fits the rate
+// limit budget first and retrying after the Retry-After delay when Binance returns 429.
+// build is called for every attempt with the current host of pool, so signed
+// requests get a fresh timestamp. Connection errors and 5xx responses switch
+// pool to its next host; requests that are safe to repeat (everything but
+// POST) are then retried there.
+func (b *HttpRequest) do(ctx context.Context, pool *endpointPool, method string, build func(baseURL string) (*http.Request, error), weight int, isOrder bool) (*http.Response, []byte, error) {
+	failovers := 0
+	canFailover := func() bool {
+		failovers++
+		return method != "POST" && failovers < pool.size()
+	}
+
+	for attempt := 0; ; attempt++ {
+		if err := b.limiter.wait(ctx, weight, isOrder); err != nil {
+			return nil, nil, fmt.Errorf("%w: %w", errNotSent, err)
+		}
+
+		baseURL := pool.get()
+		req, err := build(baseURL)
+		if err != nil {
+			b.limiter.release(weight)
+			return nil, nil, fmt.Errorf("%w: failed to create request: %w", errNotSent, err)
+		}
+
+		resp, err := b.Client.Do(req)
+		if err != nil {
+			b.limiter.release(weight)
+			if ctx.Err() != nil {
+				return nil, nil, err
+			}
+			pool.failover(baseURL)
+			if canFailover() {
+				continue
+			}
+			return nil, nil, err
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		b.limiter.update(resp, weight)
+		if resp.StatusCode == http.StatusTooManyRequests && attempt < maxRetries {
+			continue
+		}
+		if resp.StatusCode >= http.StatusInternalServerError {
+			pool.failover(baseURL)
+			if canFailover() {
+				continue
+			}
+		}
+		return resp, body, nil
+	}
 }
 
 // SignedRequest sends a signed request to Binance API
 func (b *HttpRequest) SignedRequest(method, endpoint string, params map[string]string) ([]byte, error) {
+	return b.SignedRequestContext(context.Background(), method, endpoint, params)
+}
+
+// SignedRequestContext is SignedRequest with a context that cancels the call
+func (b *HttpRequest) SignedRequestContext(ctx context.Context, method, endpoint string, params map[string]string) ([]byte, error) {
 	values := url.Values{}
 	for k, v := range params {
 		values.Add(k, v)
 	}
 
-	// add timestamp
-	values.Add("timestamp", fmt.Sprintf("%d", time.Now().UnixMilli()))
+	if b.RecvWindow > 0 {
+		values.Set("recvWindow", strconv.FormatInt(b.RecvWindow.Milliseconds(), 10))
+	}
 
-	// sign
-	query := values.Encode()
-	signature := b.signPayload(query)
-	values.Add("signature", signature)
+	isOrder := method != "GET" && strings.HasPrefix(endpoint, "/api/v3/order")
+	weight := requestWeight(method, endpoint, params)
+	for attempt := 0; ; attempt++ {
+		resp, body, err := b.do(ctx, b.rest, method, func(baseURL string) (*http.Request, error) {
+			// add timestamp
+			values.Set("timestamp", fmt.Sprintf("%d", b.serverNow(ctx).UnixMilli()))
 
-	reqURL := fmt.Sprintf("%s%s?%s", b.BaseURL, endpoint, values.Encode())
+			// sign
+			query := values.Encode()
+			signature, err := b.Signer.Sign(query)
+			if err != nil {
+				return nil, err
+			}
 
-	req, err := http.NewRequest(method, reqURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+			reqURL := fmt.Sprintf("%s%s?%s&signature=%s", baseURL, endpoint, query, url.QueryEscape(signature))
+			req, err := http.NewRequestWithContext(ctx, method, reqURL, nil)
+			if err != nil {
+				return nil, err
+			}
+			req.Header.Set("X-MBX-APIKEY", b.APIKey)
+			return req, nil
+		}, weight, isOrder)
+		if err != nil {
+			return nil, fmt.Errorf("failed to call Binance API: %w", err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			apiErr := newAPIError(resp.StatusCode, body)
+			// timestamp outside recvWindow; re-sync the clock and try once more
+			if errors.Is(apiErr, ErrTimestampOutsideRecvWindow) && attempt == 0 {
+				if err := b.syncTime(ctx); err == nil {
+					continue
+				}
+			}
+			return nil, apiErr
+		}
+
+		return body, nil
 	}
-	req.Header.Set("X-MBX-APIKEY", b.APIKey)
+}
 
-	resp, err := b.Client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to call Binance API: %w", err)
+// PublicRequest sends a public (non-signed) request to Binance
+func (b *HttpRequest) PublicRequest(endpoint string, params map[string]string) ([]byte, error) {
+	return b.PublicRequestContext(context.Background(), endpoint, params)
+}
+
+// PublicRequestContext is PublicRequest with a context that cancels the call
+func (b *HttpRequest) PublicRequestContext(ctx context.Context, endpoint string, params map[string]string) ([]byte, error) {
+	values := url.Values{}
+	for k, v := range params {
+		values.Add(k, v)
 	}
-	defer resp.Body.Close()
 
-	body, _ := io.ReadAll(resp.Body)
+	resp, body, err := b.do(ctx, b.public, "GET", func(baseURL string) (*http.Request, error) {
+		reqURL := fmt.Sprintf("%s%s?%s", baseURL, endpoint, values.Encode())
+		return http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	}, requestWeight("GET", endpoint, params), false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call Binance public API: %w", err)
+	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("Binance API error (%d): %s", resp.StatusCode, string(body))
+		return nil, newAPIError(resp.StatusCode, body)
 	}
 
 	return body, nil
 }
 
-// PublicRequest sends a public (non-signed) request to Binance
-func (b *HttpRequest) PublicRequest(endpoint string, params map[string]string) ([]byte, error) {
+// APIKeyRequestContext sends a request that carries the API key but no
+// signature, as required by the user data stream endpoints
+func (b *HttpRequest) APIKeyRequestContext(ctx context.Context, method, endpoint string, params map[string]string) ([]byte, error) {
 	values := url.Values{}
 	for k, v := range params {
 		values.Add(k, v)
 	}
 
-	reqURL := fmt.Sprintf("%s%s?%s", b.BaseURL, endpoint, values.Encode())
-
-	resp, err := b.Client.Get(reqURL)
+	resp, body, err := b.do(ctx, b.rest, method, func(baseURL string) (*http.Request, error) {
+		reqURL := fmt.Sprintf("%s%s?%s", baseURL, endpoint, values.Encode())
+		req, err := http.NewRequestWithContext(ctx, method, reqURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("X-MBX-APIKEY", b.APIKey)
+		return req, nil
+	}, requestWeight(method, endpoint, params), false)
 	if err != nil {
-		return nil, fmt.Errorf("failed to call Binance public API: %w", err)
+		return nil, fmt.Errorf("failed to call Binance API: %w", err)
 	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("Binance public API error (%d): %s", resp.StatusCode, string(body))
+		return nil, newAPIError(resp.StatusCode, body)
 	}
 
 	return body, nil
@@ -0,0 +1,151 @@
+package binance
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// orderLookupDelays are the pauses before each lookup of an order whose
+// outcome is unknown; Binance may still be processing it right after a
+// timeout or 5xx, so the first lookup can miss it
+var orderLookupDelays = []time.Duration{time.Second, 2 * time.Second, 4 * time.Second}
+
+// OrderUnknownError is returned when an order was sent but neither the
+// response nor a follow-up lookup by ClientOrderID told whether it was placed.
+// Query the order by ClientOrderID before placing it again.
+type OrderUnknownError struct {
+	Symbol        string
+	ClientOrderID string
+	Err           error
+}
+
+func (e *OrderUnknownError) Error() string {
+	return fmt.Sprintf("order %s on %s in unknown state: %v", e.ClientOrderID, e.Symbol, e.Err)
+}
+
+func (e *OrderUnknownError) Unwrap() error {
+	return e.Err
+}
+
+// MaxClientOrderIDLen is the longest clientOrderId Binance accepts
+const MaxClientOrderIDLen = 36
+
+// ClientOrderID returns the client order ID prefix+kind-symbol-minute, so
+// the same intent sent again within the minute maps to the same ID. When
+// that is longer than MaxClientOrderIDLen, symbol and minute are replaced
+// by a hash of them, cut to fit.
+func ClientOrderID(prefix, kind, symbol string, at time.Time) string {
+	minute := strconv.FormatInt(at.Truncate(time.Minute).Unix(), 36)
+	id := prefix + kind + "-" + symbol + "-" + minute
+	if len(id) <= MaxClientOrderIDLen {
+		return id
+	}
+	sum := sha256.Sum256([]byte(symbol + "-" + minute))
+	id = prefix + kind + "-" + hex.EncodeToString(sum[:])
+	return id[:MaxClientOrderIDLen]
+}
+
+// deterministicClientOrderID is the ClientOrderID of orders sent without
+// one; the kind is a digest of the order parameters, so different orders on
+// the same symbol within a minute don't share an ID
+func deterministicClientOrderID(params map[string]string, at time.Time) string {
+	h := sha256.New()
+	for _, k := range []string{"symbol", "side", "type", "quantity", "quoteOrderQty", "price", "stopPrice",
+		"abovePrice", "aboveStopPrice", "belowPrice", "belowStopPrice"} {
+		fmt.Fprintf(h, "%s=%s&", k, params[k])
+	}
+	return ClientOrderID("x-", hex.EncodeToString(h.Sum(nil))[:8], params["symbol"], at)
+}
+
+// outcomeUnknown reports whether err leaves it open whether Binance
+// executed the request: transport errors, including a context cancelled
+// while the request was in flight, and 5xx responses
+func outcomeUnknown(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= http.StatusInternalServerError
+	}
+	return !errors.Is(err, errNotSent)
+}
+
+// submitOnce sends a new order request that carries clientOrderID. When the
+// outcome is unknown it looks the order up by that ID with lookup a few
+// times, giving Binance time to process it. The order is never resent: if
+// it doesn't show up, an *OrderUnknownError leaves the decision to the caller.
+// Binance accepts a reused clientOrderId once the first order has filled, so
+// a resend could place it twice.
+func (b *HttpRequest) submitOnce(ctx context.Context, endpoint, symbol, clientOrderID string, params map[string]string, lookup func() ([]byte, error)) ([]byte, error) {
+	body, err := b.SignedRequestContext(ctx, "POST", endpoint, params)
+	if err == nil || !outcomeUnknown(err) {
+		return body, err
+	}
+	log.Printf("⚠️  %s: order %s outcome unknown (%v), looking it up\n", symbol, clientOrderID, err)
+
+	for _, delay := range orderLookupDelays {
+		select {
+		case <-ctx.Done():
+			return nil, &OrderUnknownError{Symbol: symbol, ClientOrderID: clientOrderID, Err: err}
+		case <-time.After(delay):
+		}
+
+		body, lookupErr := lookup()
+		if lookupErr == nil {
+			log.Printf("🔎 %s: order %s was placed\n", symbol, clientOrderID)
+			return body, nil
+		}
+		if !errors.Is(lookupErr, ErrNoSuchOrder) {
+			log.Printf("⚠️  %s: order %s lookup failed: %v\n", symbol, clientOrderID, lookupErr)
+		}
+	}
+	return nil, &OrderUnknownError{Symbol: symbol, ClientOrderID: clientOrderID, Err: err}
+}
+
+// GetOrderByClientID returns the current status of an order by its clientOrderId
+func (b *HttpRequest) GetOrderByClientID(symbol, clientOrderID string) (*OrderResult, error) {
+	return b.GetOrderByClientIDContext(context.Background(), symbol, clientOrderID)
+}
+
+// GetOrderByClientIDContext is GetOrderByClientID with a context
+func (b *HttpRequest) GetOrderByClientIDContext(ctx context.Context, symbol, clientOrderID string) (*OrderResult, error) {
+	body, err := b.lookupOrder(ctx, symbol, clientOrderID)
+	if err != nil {
+		return nil, err
+	}
+	return parseOrderResult(body)
+}
+
+func (b *HttpRequest) lookupOrder(ctx context.Context, symbol, clientOrderID string) ([]byte, error) {
+	body, err := b.SignedRequestContext(ctx, "GET", "/api/v3/order", map[string]string{
+		"symbol":            symbol,
+		"origClientOrderId": clientOrderID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query order: %w", err)
+	}
+	return body, nil
+}
+
+func (b *HttpRequest) lookupOrderList(ctx context.Context, listClientOrderID string) ([]byte, error) {
+	body, err := b.SignedRequestContext(ctx, "GET", "/api/v3/orderList", map[string]string{
+		"origClientOrderId": listClientOrderID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query order list: %w", err)
+	}
+	return body, nil
+}
+
+// ensureClientOrderID makes sure params carry a client ID and returns it
+func (b *HttpRequest) ensureClientOrderID(ctx context.Context, params map[string]string, key string) string {
+	if params[key] == "" {
+		params[key] = deterministicClientOrderID(params, b.serverNow(ctx))
+	}
+	return params[key]
+}
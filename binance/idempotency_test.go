@@ -0,0 +1,163 @@
+package binance
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// orderServer answers POST /api/v3/order with a 503 and GET /api/v3/order
+// with -2013 until foundAfter lookups have been made
+func orderServer(t *testing.T, foundAfter int) (*HttpRequest, *atomic.Int32, *atomic.Int32) {
+	var posts, lookups atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			posts.Add(1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"code":-1000,"msg":"Unknown error, please check your request or try again later."}`))
+			return
+		}
+		if int(lookups.Add(1)) < foundAfter || foundAfter == 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"code":-2013,"msg":"Order does not exist."}`))
+			return
+		}
+		w.Write([]byte(`{"symbol":"BTCUSDT","orderId":1,"clientOrderId":"x-1","status":"FILLED","executedQty":"1"}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	b := NewHttpRequest("key", "secret")
+	b.TimeSyncInterval = 0
+	b.SetEndpoints(Endpoints{REST: []string{srv.URL}})
+	return b, &posts, &lookups
+}
+
+func TestSubmitOnceNeverResends(t *testing.T) {
+	defer func(d []time.Duration) { orderLookupDelays = d }(orderLookupDelays)
+	orderLookupDelays = []time.Duration{time.Millisecond, time.Millisecond, time.Millisecond}
+
+	t.Run("found on a later lookup", func(t *testing.T) {
+		b, posts, lookups := orderServer(t, 2)
+		body, err := b.submitOnce(context.Background(), "/api/v3/order", "BTCUSDT", "x-1", map[string]string{"symbol": "BTCUSDT"}, func() ([]byte, error) {
+			return b.lookupOrder(context.Background(), "BTCUSDT", "x-1")
+		})
+		if err != nil || body == nil {
+			t.Fatalf("err = %v, want the looked up order", err)
+		}
+		if posts.Load() != 1 || lookups.Load() != 2 {
+			t.Fatalf("posts = %d, lookups = %d, want 1 and 2", posts.Load(), lookups.Load())
+		}
+	})
+
+	t.Run("never found", func(t *testing.T) {
+		b, posts, lookups := orderServer(t, 0)
+		_, err := b.submitOnce(context.Background(), "/api/v3/order", "BTCUSDT", "x-1", map[string]string{"symbol": "BTCUSDT"}, func() ([]byte, error) {
+			return b.lookupOrder(context.Background(), "BTCUSDT", "x-1")
+		})
+		var unknownErr *OrderUnknownError
+		if !errors.As(err, &unknownErr) {
+			t.Fatalf("err = %v, want *OrderUnknownError", err)
+		}
+		if posts.Load() != 1 || int(lookups.Load()) != len(orderLookupDelays) {
+			t.Fatalf("posts = %d, lookups = %d, want 1 and %d", posts.Load(), lookups.Load(), len(orderLookupDelays))
+		}
+	})
+
+	t.Run("cancelled before sending", func(t *testing.T) {
+		b, posts, _ := orderServer(t, 0)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := b.submitOnce(ctx, "/api/v3/order", "BTCUSDT", "x-1", map[string]string{"symbol": "BTCUSDT"}, func() ([]byte, error) {
+			return b.lookupOrder(ctx, "BTCUSDT", "x-1")
+		})
+		var unknownErr *OrderUnknownError
+		if errors.As(err, &unknownErr) || !errors.Is(err, context.Canceled) || posts.Load() != 0 {
+			t.Fatalf("err = %v, posts = %d, want a plain cancellation and nothing sent", err, posts.Load())
+		}
+	})
+}
+
+func TestOutcomeUnknown(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"5xx", &APIError{StatusCode: 503, Code: -1000}, true},
+		{"rejected", &APIError{StatusCode: 400, Code: -2010}, false},
+		{"cancelled in flight", context.Canceled, true},
+		{"not sent", errNotSent, false},
+	}
+	for _, tt := range tests {
+		if got := outcomeUnknown(tt.err); got != tt.want {
+			t.Errorf("%s: outcomeUnknown = %t, want %t", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCancelReplaceLooksUpNewOrder(t *testing.T) {
+	defer func(d []time.Duration) { orderLookupDelays = d }(orderLookupDelays)
+	orderLookupDelays = []time.Duration{time.Millisecond, time.Millisecond, time.Millisecond}
+	req := OrderRequest{Symbol: "BTCUSDT", Side: "SELL", Type: OrderTypeLimit, Quantity: 1, Price: 100, ClientOrderID: "x-1"}
+
+	t.Run("found", func(t *testing.T) {
+		b, posts, _ := orderServer(t, 1)
+		b.filters["BTCUSDT"] = &SymbolFilters{Symbol: "BTCUSDT"}
+		result, err := b.CancelReplaceContext(context.Background(), 7, req, "")
+		if err != nil {
+			t.Fatalf("err = %v, want the looked up order", err)
+		}
+		if posts.Load() != 1 || result.Order == nil || result.Order.ClientOrderID != "x-1" || result.CancelResult != "" {
+			t.Fatalf("posts = %d, result = %+v, want one post and the new order with an unknown cancel", posts.Load(), result)
+		}
+	})
+
+	t.Run("never found", func(t *testing.T) {
+		b, posts, _ := orderServer(t, 0)
+		b.filters["BTCUSDT"] = &SymbolFilters{Symbol: "BTCUSDT"}
+		_, err := b.CancelReplaceContext(context.Background(), 7, req, "")
+		var unknownErr *OrderUnknownError
+		if !errors.As(err, &unknownErr) || posts.Load() != 1 {
+			t.Fatalf("err = %v, posts = %d, want *OrderUnknownError after one post", err, posts.Load())
+		}
+	})
+}
+
+func TestClientOrderIDLength(t *testing.T) {
+	at := time.Date(2024, 5, 1, 12, 30, 45, 0, time.UTC)
+	tests := []struct {
+		name, prefix, kind, symbol string
+	}{
+		{"short symbol", "atb-", "sell", "BTCUSDT"},
+		{"long symbol", "atb-", "sell", "1000SATSFDUSDBTCUSDTETH"},
+		{"parameter digest", "x-", "0123abcd", "1000SATSFDUSD"},
+	}
+	for _, tt := range tests {
+		id := ClientOrderID(tt.prefix, tt.kind, tt.symbol, at)
+		if len(id) > MaxClientOrderIDLen {
+			t.Errorf("%s: %q is %d characters, want at most %d", tt.name, id, len(id), MaxClientOrderIDLen)
+		}
+		if !strings.HasPrefix(id, tt.prefix+tt.kind+"-") {
+			t.Errorf("%s: %q lost its prefix and kind", tt.name, id)
+		}
+		if id != ClientOrderID(tt.prefix, tt.kind, tt.symbol, at.Add(10*time.Second)) {
+			t.Errorf("%s: ID changed within the minute", tt.name)
+		}
+		if id == ClientOrderID(tt.prefix, tt.kind, tt.symbol, at.Add(time.Minute)) {
+			t.Errorf("%s: ID reused in the next minute", tt.name)
+		}
+	}
+
+	if got := ClientOrderID("atb-", "sell", "BTCUSDT", at); got != "atb-sell-BTCUSDT-"+strconv.FormatInt(at.Truncate(time.Minute).Unix(), 36) {
+		t.Errorf("short IDs should stay readable, got %q", got)
+	}
+	if ClientOrderID("atb-", "sell", "1000SATSFDUSDBTCUSDTETH", at) == ClientOrderID("atb-", "sell", "1000SATSFDUSDBTCUSDTBNB", at) {
+		t.Error("long symbols sharing a prefix got the same ID")
+	}
+}
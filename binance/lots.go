@@ -0,0 +1,253 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Lot accounting methods: which lots a sale consumes first
+const (
+	LotMethodFIFO = "FIFO" // oldest lot first
+	LotMethodLIFO = "LIFO" // newest lot first
+	LotMethodHIFO = "HIFO" // most expensive lot first
+)
+
+// lotDust is the quantity below which a lot counts as used up
+const lotDust = 1e-12
+
+// Lot is a quantity bought (or received) in one go that is still held
+type Lot struct {
+	Time   time.Time
+	Qty    float64 // quantity still open
+	Price  float64 // cost per unit in the reporting currency, buy fees included
+	Source string  // TradeSource of the buy
+}
+
+// Sale is the disposal of a quantity matched against open lots
+type Sale struct {
+	Time        time.Time
+	Qty         float64
+	Proceeds    float64 // received in the reporting currency, sell fees deducted
+	CostBasis   float64 // cost of the lots consumed
+	RealizedPnL float64 // Proceeds - CostBasis
+	Source      string  // TradeSource of the sell
+}
+
+// LotLedger is the lot by lot position of one asset built from its trade ledger
+type LotLedger struct {
+	Asset      string
+	Method     string
+	OpenLots   []Lot
+	Sales      []Sale
+	Commission float64 // total fees paid, in the reporting currency
+	Unmatched  float64 // quantity sold or withdrawn without a lot to match, i.e. missing history
+
+	boughtQty   float64
+	boughtValue float64
+}
+
+// BuildLotLedger replays trades in chronological order: every buy opens a
+// lot, every sell consumes lots by method and realizes PnL. Withdrawals
+// consume lots without realizing anything.
+func BuildLotLedger(asset string, trades []Trade, method string) (*LotLedger, error) {
+	method = strings.ToUpper(method)
+	switch method {
+	case "":
+		method = LotMethodFIFO
+	case LotMethodFIFO, LotMethodLIFO, LotMethodHIFO:
+	default:
+		return nil, fmt.Errorf("unknown lot method %q (FIFO, LIFO, HIFO)", method)
+	}
+
+	trades = append([]Trade(nil), trades...)
+	sort.SliceStable(trades, func(i, j int) bool {
+		return trades[i].Time.Before(trades[j].Time)
+	})
+
+	l := &LotLedger{Asset: asset, Method: method}
+	for _, t := range trades {
+		l.Commission += t.CommissionQuote
+
+		// fees taken in the base asset are paid with units of the position
+		// itself; other fees are an extra cost (buy) or lower proceeds (sell)
+		baseFee, quoteFee := 0.0, t.CommissionQuote
+		if t.CommissionAsset == asset {
+			baseFee, quoteFee = t.Commission, 0
+		}
+
+		if t.IsBuyer {
+			qty := t.Qty - baseFee
+			if qty <= 0 {
+				continue
+			}
+			cost := t.Price*t.Qty + quoteFee
+			l.boughtQty += qty
+			l.boughtValue += cost
+			l.OpenLots = append(l.OpenLots, Lot{Time: t.Time, Qty: qty, Price: cost / qty, Source: t.Source})
+			continue
+		}
+
+		consumed, costBasis := l.consume(t.Qty + baseFee)
+		if t.Source == TradeSourceWithdrawal || consumed <= 0 {
+			continue
+		}
+		// proceeds of the part that matched a lot
+		proceeds := (t.Price*t.Qty - quoteFee) * consumed / (t.Qty + baseFee)
+		l.Sales = append(l.Sales, Sale{
+			Time:        t.Time,
+			Qty:         consumed,
+			Proceeds:    proceeds,
+			CostBasis:   costBasis,
+			RealizedPnL: proceeds - costBasis,
+			Source:      t.Source,
+		})
+	}
+	return l, nil
+}
+
+// consume takes qty out of the open lots in the order of the ledger method
+// and returns the quantity matched and its cost
+func (l *LotLedger) consume(qty float64) (consumed, cost float64) {
+	for qty > lotDust && len(l.OpenLots) > 0 {
+		i := l.nextLot()
+		lot := &l.OpenLots[i]
+		take := math.Min(qty, lot.Qty)
+		consumed += take
+		cost += take * lot.Price
+		qty -= take
+		lot.Qty -= take
+		if lot.Qty <= lotDust {
+			l.OpenLots = append(l.OpenLots[:i], l.OpenLots[i+1:]...)
+		}
+	}
+	if qty > lotDust {
+		l.Unmatched += qty
+	}
+	return consumed, cost
+}
+
+// nextLot returns the index of the lot a sale consumes next
+func (l *LotLedger) nextLot() int {
+	switch l.Method {
+	case LotMethodLIFO:
+		return len(l.OpenLots) - 1
+	case LotMethodHIFO:
+		best := 0
+		for i, lot := range l.OpenLots {
+			if lot.Price > l.OpenLots[best].Price {
+				best = i
+			}
+		}
+		return best
+	}
+	return 0
+}
+
+// Quantity returns the quantity still held in open lots
+func (l *LotLedger) Quantity() float64 {
+	qty := 0.0
+	for _, lot := range l.OpenLots {
+		qty += lot.Qty
+	}
+	return qty
+}
+
+// CostPrice returns the average cost per unit of the open lots
+func (l *LotLedger) CostPrice() float64 {
+	qty, cost := 0.0, 0.0
+	for _, lot := range l.OpenLots {
+		qty += lot.Qty
+		cost += lot.Qty * lot.Price
+	}
+	if qty <= 0 {
+		return 0
+	}
+	return cost / qty
+}
+
+// AveragePrice returns the average price of everything ever bought
+func (l *LotLedger) AveragePrice() float64 {
+	if l.boughtQty <= 0 {
+		return 0
+	}
+	return l.boughtValue / l.boughtQty
+}
+
+// RealizedPnL returns the PnL of all sales
+func (l *LotLedger) RealizedPnL() float64 {
+	pnl := 0.0
+	for _, s := range l.Sales {
+		pnl += s.RealizedPnL
+	}
+	return pnl
+}
+
+// GetLotLedger builds the lot ledger of asset from its trades, transfers and
+// conversions using LotMethod
+func (b *HttpRequest) GetLotLedger(asset string) (*LotLedger, error) {
+	return b.GetLotLedgerContext(context.Background(), asset)
+}
+
+// GetLotLedgerContext is GetLotLedger with a context
+func (b *HttpRequest) GetLotLedgerContext(ctx context.Context, asset string) (*LotLedger, error) {
+	trades, err := b.assetTrades(ctx, asset)
+	if err != nil {
+		return nil, err
+	}
+	if len(trades) == 0 {
+		return nil, fmt.Errorf("no trade history")
+	}
+	return BuildLotLedger(asset, trades, b.LotMethod)
+}
+
+// GetTradedAssets returns the assets whose ledger may hold realized PnL:
+// assets held now or at any account snapshot since startup, assets deposited,
+// withdrawn or converted since TransferHistoryStart, and PnLAssets.
+// myTrades can't be listed without a symbol, so coins only ever bought and
+// sold on spot and gone before startup have to be listed in PnLAssets.
+func (b *HttpRequest) GetTradedAssets() ([]string, error) {
+	return b.GetTradedAssetsContext(context.Background())
+}
+
+// GetTradedAssetsContext is GetTradedAssets with a context
+func (b *HttpRequest) GetTradedAssetsContext(ctx context.Context) ([]string, error) {
+	assets := make(map[string]bool)
+	for _, asset := range b.PnLAssets {
+		assets[asset] = true
+	}
+
+	b.accountMu.Lock()
+	for asset := range b.seenAssets {
+		assets[asset] = true
+	}
+	b.accountMu.Unlock()
+
+	transfers, err := b.GetTransfersContext(ctx)
+	if err != nil {
+		fmt.Printf("⚠️  wallet history unavailable: %v\n", err)
+	}
+	for _, t := range transfers {
+		assets[t.Asset] = true
+	}
+
+	conversions, err := b.GetConversionsContext(ctx)
+	if err != nil {
+		fmt.Printf("⚠️  convert history unavailable: %v\n", err)
+	}
+	for _, c := range conversions {
+		assets[c.FromAsset] = true
+		assets[c.ToAsset] = true
+	}
+
+	delete(assets, b.ReportingAsset)
+	list := make([]string, 0, len(assets))
+	for asset := range assets {
+		list = append(list, asset)
+	}
+	sort.Strings(list)
+	return list, nil
+}
@@ -0,0 +1,130 @@
+package binance
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestBuildLotLedger(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(day int) time.Time { return start.AddDate(0, 0, day) }
+	buy := func(day int, qty, price float64) Trade {
+		return Trade{Time: at(day), Qty: qty, Price: price, IsBuyer: true}
+	}
+	sell := func(day int, qty, price float64) Trade {
+		return Trade{Time: at(day), Qty: qty, Price: price}
+	}
+
+	feeBuy := buy(0, 1, 100)
+	feeBuy.Commission, feeBuy.CommissionAsset, feeBuy.CommissionQuote = 0.01, "ABC", 1
+	feeSell := sell(1, 0.99, 200)
+	feeSell.Commission, feeSell.CommissionAsset, feeSell.CommissionQuote = 0.198, "USDT", 0.198
+	withdrawal := sell(1, 1, 150)
+	withdrawal.Source = TradeSourceWithdrawal
+
+	tests := []struct {
+		name       string
+		method     string
+		trades     []Trade
+		wantPnL    []float64 // realized PnL of each sale
+		wantLots   []Lot     // open lots, Qty and Price only
+		unmatched  float64
+		commission float64
+	}{
+		{
+			name:     "FIFO sells the oldest lot",
+			method:   LotMethodFIFO,
+			trades:   []Trade{buy(0, 1, 100), buy(1, 1, 200), sell(2, 1, 300)},
+			wantPnL:  []float64{200},
+			wantLots: []Lot{{Qty: 1, Price: 200}},
+		},
+		{
+			name:     "LIFO sells the newest lot",
+			method:   LotMethodLIFO,
+			trades:   []Trade{buy(0, 1, 100), buy(1, 1, 200), sell(2, 1, 300)},
+			wantPnL:  []float64{100},
+			wantLots: []Lot{{Qty: 1, Price: 100}},
+		},
+		{
+			name:     "HIFO sells the most expensive lot",
+			method:   LotMethodHIFO,
+			trades:   []Trade{buy(0, 1, 100), buy(1, 1, 300), buy(2, 1, 200), sell(3, 1, 250)},
+			wantPnL:  []float64{-50},
+			wantLots: []Lot{{Qty: 1, Price: 100}, {Qty: 1, Price: 200}},
+		},
+		{
+			name:     "sale spans lots and leaves a partial lot",
+			method:   LotMethodFIFO,
+			trades:   []Trade{buy(0, 1, 100), buy(1, 2, 130), sell(2, 2, 150)},
+			wantPnL:  []float64{300 - 230},
+			wantLots: []Lot{{Qty: 1, Price: 130}},
+		},
+		{
+			name:     "trades are replayed in time order",
+			method:   "",
+			trades:   []Trade{sell(2, 1, 300), buy(1, 1, 200), buy(0, 1, 100)},
+			wantPnL:  []float64{200},
+			wantLots: []Lot{{Qty: 1, Price: 200}},
+		},
+		{
+			name:       "base fees shrink the lot, quote fees the proceeds",
+			method:     LotMethodFIFO,
+			trades:     []Trade{feeBuy, feeSell},
+			wantPnL:    []float64{198 - 0.198 - 100},
+			commission: 1.198,
+		},
+		{
+			name:     "withdrawals consume lots without a sale",
+			method:   LotMethodFIFO,
+			trades:   []Trade{buy(0, 2, 100), withdrawal},
+			wantLots: []Lot{{Qty: 1, Price: 100}},
+		},
+		{
+			name:      "unmatched part of a sale is not realized",
+			method:    LotMethodFIFO,
+			trades:    []Trade{buy(0, 1, 100), sell(1, 2, 150)},
+			wantPnL:   []float64{50},
+			unmatched: 1,
+		},
+	}
+
+	const eps = 1e-9
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := BuildLotLedger("ABC", tt.trades, tt.method)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(l.Sales) != len(tt.wantPnL) {
+				t.Fatalf("sales = %+v, want %d", l.Sales, len(tt.wantPnL))
+			}
+			for i, want := range tt.wantPnL {
+				if math.Abs(l.Sales[i].RealizedPnL-want) > eps {
+					t.Errorf("sale %d PnL = %v, want %v", i, l.Sales[i].RealizedPnL, want)
+				}
+			}
+			if len(l.OpenLots) != len(tt.wantLots) {
+				t.Fatalf("open lots = %+v, want %+v", l.OpenLots, tt.wantLots)
+			}
+			for i, want := range tt.wantLots {
+				got := l.OpenLots[i]
+				if math.Abs(got.Qty-want.Qty) > eps || math.Abs(got.Price-want.Price) > eps {
+					t.Errorf("lot %d = %v @ %v, want %v @ %v", i, got.Qty, got.Price, want.Qty, want.Price)
+				}
+			}
+			if math.Abs(l.Unmatched-tt.unmatched) > eps {
+				t.Errorf("unmatched = %v, want %v", l.Unmatched, tt.unmatched)
+			}
+			if math.Abs(l.Commission-tt.commission) > eps {
+				t.Errorf("commission = %v, want %v", l.Commission, tt.commission)
+			}
+		})
+	}
+}
+
+func TestBuildLotLedgerUnknownMethod(t *testing.T) {
+	if _, err := BuildLotLedger("ABC", nil, "AVG"); err == nil {
+		t.Fatal("want an error for an unknown method")
+	}
+}
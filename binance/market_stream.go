@@ -0,0 +1,407 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	streamMaxLifetime  = 23*time.Hour + 30*time.Minute // Binance drops connections after 24h
+	streamPongWait     = time.Minute                   // server pings every 20s
+	streamMaxBackoff   = time.Minute
+	streamMaxCandles   = 500
+	streamCacheMaxAge  = time.Minute // cached data older than this is ignored
+	streamWriteTimeout = 10 * time.Second
+)
+
+// MarketCache holds the latest prices and candles received from market streams
+type MarketCache struct {
+	mu       sync.RWMutex
+	prices   map[string]float64
+	pricesAt map[string]time.Time
+	klines   map[string][]Kline // by symbol@interval
+	klinesAt map[string]time.Time
+}
+
+// NewMarketCache creates an empty MarketCache
+func NewMarketCache() *MarketCache {
+	return &MarketCache{
+		prices:   make(map[string]float64),
+		pricesAt: make(map[string]time.Time),
+		klines:   make(map[string][]Kline),
+		klinesAt: make(map[string]time.Time),
+	}
+}
+
+// Price returns the last streamed price for symbol if it is still fresh
+func (c *MarketCache) Price(symbol string) (float64, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if time.Since(c.pricesAt[symbol]) > streamCacheMaxAge {
+		return 0, false
+	}
+	return c.prices[symbol], true
+}
+
+// Klines returns the last limit candles for symbol/interval if the cache is
+// fresh and holds at least that many
+func (c *MarketCache) Klines(symbol, interval string, limit int) ([]Kline, bool) {
+	key := symbol + "@" + interval
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	klines := c.klines[key]
+	if time.Since(c.klinesAt[key]) > streamCacheMaxAge || len(klines) < limit {
+		return nil, false
+	}
+	return append([]Kline(nil), klines[len(klines)-limit:]...), true
+}
+
+func (c *MarketCache) setPrice(symbol string, price float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.prices[symbol] = price
+	c.pricesAt[symbol] = time.Now()
+}
+
+func (c *MarketCache) setKlines(symbol, interval string, klines []Kline) {
+	key := symbol + "@" + interval
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.klines[key] = klines
+	c.klinesAt[key] = time.Now()
+}
+
+// updateKline replaces the open candle or appends a new one
+func (c *MarketCache) updateKline(symbol, interval string, k Kline) {
+	key := symbol + "@" + interval
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	klines := c.klines[key]
+	n := len(klines)
+	switch {
+	case n > 0 && klines[n-1].OpenTime.Equal(k.OpenTime):
+		klines[n-1] = k
+	case n == 0 || k.OpenTime.After(klines[n-1].OpenTime):
+		klines = append(klines, k)
+		if len(klines) > streamMaxCandles {
+			klines = klines[len(klines)-streamMaxCandles:]
+		}
+	default:
+		return // out of order update for an older candle
+	}
+	c.klines[key] = klines
+	c.klinesAt[key] = time.Now()
+}
+
+// MarketStream keeps a combined @kline_<interval> and @miniTicker websocket
+// open for a set of symbols and feeds the updates into a MarketCache.
+// It reconnects automatically and rolls the connection over before Binance's
+// 24h limit.
+type MarketStream struct {
+	URLs      []string // websocket base URLs; a failed connect moves on to the next
+	Intervals []string // kline intervals to stream, e.g. 4h, 1d
+	Cache     *MarketCache
+	OnPrice   func(symbol string, price float64) // called on every mini ticker; must not block
+
+	rest Exchange // seeds the candle history on every (re)connect
+	url  int      // index into URLs of the host in use
+
+	mu          sync.Mutex
+	symbols     []string
+	resubscribe chan struct{}
+}
+
+// NewMarketStream creates a MarketStream that seeds candles from rest
+func NewMarketStream(rest Exchange, intervals ...string) *MarketStream {
+	return &MarketStream{
+		URLs:        MainnetEndpoints.Stream,
+		Intervals:   intervals,
+		Cache:       NewMarketCache(),
+		rest:        rest,
+		resubscribe: make(chan struct{}, 1),
+	}
+}
+
+// Subscribe sets the symbols to stream, reconnecting if the set changed
+func (s *MarketStream) Subscribe(symbols []string) {
+	symbols = slices.Clone(symbols)
+	symbols = slices.DeleteFunc(symbols, func(sym string) bool { return sym == "" })
+	slices.Sort(symbols)
+	symbols = slices.Compact(symbols)
+
+	s.mu.Lock()
+	changed := !slices.Equal(s.symbols, symbols)
+	s.symbols = symbols
+	s.mu.Unlock()
+
+	if changed {
+		select {
+		case s.resubscribe <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Run keeps the stream connected until ctx is cancelled
+func (s *MarketStream) Run(ctx context.Context) error {
+	backoff := time.Second
+	for {
+		start := time.Now()
+		err := s.runOnce(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err == nil {
+			backoff = time.Second
+			continue
+		}
+
+		if time.Since(start) > streamMaxBackoff {
+			backoff = time.Second // the connection was healthy for a while
+		}
+		log.Printf("⚠️  Market stream disconnected: %v. Reconnecting in %s\n", err, backoff)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, streamMaxBackoff)
+	}
+}
+
+// runOnce serves a single connection. It returns nil when the connection
+// was closed on purpose (resubscribe or 24h rollover).
+func (s *MarketStream) runOnce(ctx context.Context) error {
+	s.mu.Lock()
+	symbols := s.symbols
+	s.mu.Unlock()
+
+	if len(symbols) == 0 {
+		select {
+		case <-ctx.Done():
+		case <-s.resubscribe:
+		}
+		return nil
+	}
+
+	var streams []string
+	for _, symbol := range symbols {
+		lower := strings.ToLower(symbol)
+		streams = append(streams, lower+"@miniTicker")
+		for _, interval := range s.Intervals {
+			streams = append(streams, lower+"@kline_"+interval)
+		}
+	}
+
+	conn, err := dialStream(ctx, s.URLs[s.url%len(s.URLs)]+"/stream?streams="+strings.Join(streams, "/"))
+	if err != nil {
+		s.url++
+		return fmt.Errorf("failed to connect market stream: %w", err)
+	}
+	defer conn.Close()
+	log.Printf("📡 Market stream connected: %d symbols\n", len(symbols))
+
+	readErr := readStream(conn, s.handleMessage)
+
+	s.seed(ctx, symbols)
+
+	rollover := time.NewTimer(streamMaxLifetime)
+	defer rollover.Stop()
+
+	select {
+	case <-ctx.Done():
+		closeStream(conn)
+		return nil
+	case <-s.resubscribe:
+		return nil
+	case <-rollover.C:
+		log.Println("🔄 Market stream 24h rollover")
+		return nil
+	case err := <-readErr:
+		return err
+	}
+}
+
+// dialStream opens a websocket that answers server pings and treats them
+// as a liveness signal
+func dialStream(ctx context.Context, url string) (*websocket.Conn, error) {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	conn.SetReadDeadline(time.Now().Add(streamPongWait))
+	conn.SetPingHandler(func(data string) error {
+		conn.SetReadDeadline(time.Now().Add(streamPongWait))
+		return conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(streamWriteTimeout))
+	})
+	return conn, nil
+}
+
+// readStream passes every message to handle until the connection fails;
+// the error is delivered on the returned channel
+func readStream(conn *websocket.Conn, handle func([]byte)) <-chan error {
+	readErr := make(chan error, 1)
+	go func() {
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				readErr <- err
+				return
+			}
+			conn.SetReadDeadline(time.Now().Add(streamPongWait))
+			handle(data)
+		}
+	}()
+	return readErr
+}
+
+// closeStream tells the server we are leaving
+func closeStream(conn *websocket.Conn) {
+	conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(streamWriteTimeout))
+}
+
+// seed loads candle history over REST so indicators have enough data
+func (s *MarketStream) seed(ctx context.Context, symbols []string) {
+	for _, symbol := range symbols {
+		for _, interval := range s.Intervals {
+			klines, err := s.rest.GetKlinesContext(ctx, symbol, interval, streamMaxCandles)
+			if err != nil {
+				log.Printf("⚠️  %s: cannot seed %s candles: %v\n", symbol, interval, err)
+				continue
+			}
+			s.Cache.setKlines(symbol, interval, klines)
+		}
+	}
+}
+
+func (s *MarketStream) handleMessage(data []byte) {
+	var msg struct {
+		Stream string          `json:"stream"`
+		Data   json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(data, &msg); err != nil {
+		log.Printf("⚠️  Market stream: bad message: %v\n", err)
+		return
+	}
+
+	// encoding/json matches keys case-insensitively, so single letter keys
+	// whose other case is also sent (E/e, L/l, V/v) need a field of their own
+	var event struct {
+		Event     string `json:"e"`
+		EventTime int64  `json:"E"`
+		Symbol    string `json:"s"`
+		Close     string `json:"c"`
+		Kline     struct {
+			OpenTime     int64  `json:"t"`
+			CloseTime    int64  `json:"T"`
+			Interval     string `json:"i"`
+			Open         string `json:"o"`
+			Close        string `json:"c"`
+			High         string `json:"h"`
+			Low          string `json:"l"`
+			LastTradeID  int64  `json:"L"`
+			Volume       string `json:"v"`
+			TakerBuyBase string `json:"V"`
+		} `json:"k"`
+	}
+	if err := json.Unmarshal(msg.Data, &event); err != nil {
+		log.Printf("⚠️  Market stream: bad %s event: %v\n", msg.Stream, err)
+		return
+	}
+
+	switch event.Event {
+	case "24hrMiniTicker":
+		price, _ := strconv.ParseFloat(event.Close, 64)
+		s.Cache.setPrice(event.Symbol, price)
+		if s.OnPrice != nil {
+			s.OnPrice(event.Symbol, price)
+		}
+	case "kline":
+		k := event.Kline
+		open, _ := strconv.ParseFloat(k.Open, 64)
+		high, _ := strconv.ParseFloat(k.High, 64)
+		low, _ := strconv.ParseFloat(k.Low, 64)
+		closeP, _ := strconv.ParseFloat(k.Close, 64)
+		vol, _ := strconv.ParseFloat(k.Volume, 64)
+		s.Cache.updateKline(event.Symbol, k.Interval, Kline{
+			OpenTime:  time.UnixMilli(k.OpenTime),
+			Open:      open,
+			High:      high,
+			Low:       low,
+			Close:     closeP,
+			Volume:    vol,
+			CloseTime: time.UnixMilli(k.CloseTime),
+		})
+	}
+}
+
+// StreamingExchange serves prices and klines from a MarketStream cache,
+// falling back to the wrapped Exchange when the cache has no fresh data.
+// Holdings returned by GetAccountBalancesContext are subscribed automatically.
+type StreamingExchange struct {
+	Exchange
+	stream *MarketStream
+}
+
+// NewStreamingExchange wraps ex with the market data of stream
+func NewStreamingExchange(ex Exchange, stream *MarketStream) *StreamingExchange {
+	return &StreamingExchange{Exchange: ex, stream: stream}
+}
+
+// GetAccountBalancesContext fetches balances and subscribes their symbols
+func (e *StreamingExchange) GetAccountBalancesContext(ctx context.Context) ([]AccountBalance, error) {
+	balances, err := e.Exchange.GetAccountBalancesContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	symbols := make([]string, 0, len(balances))
+	for _, balance := range balances {
+		symbols = append(symbols, balance.Symbol)
+	}
+	e.stream.Subscribe(symbols)
+	return balances, nil
+}
+
+// GetPricesContext returns streamed prices, asking the wrapped Exchange for
+// the symbols that have none
+func (e *StreamingExchange) GetPricesContext(ctx context.Context, symbols []string) (map[string]float64, error) {
+	prices := make(map[string]float64, len(symbols))
+	var missing []string
+	for _, symbol := range symbols {
+		if price, ok := e.stream.Cache.Price(symbol); ok {
+			prices[symbol] = price
+		} else {
+			missing = append(missing, symbol)
+		}
+	}
+	if len(missing) == 0 {
+		return prices, nil
+	}
+
+	fetched, err := e.Exchange.GetPricesContext(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+	for symbol, price := range fetched {
+		prices[symbol] = price
+	}
+	return prices, nil
+}
+
+// GetKlinesContext returns streamed candles, or asks the wrapped Exchange
+func (e *StreamingExchange) GetKlinesContext(ctx context.Context, symbol, interval string, limit int) ([]Kline, error) {
+	if klines, ok := e.stream.Cache.Klines(symbol, interval, limit); ok {
+		return klines, nil
+	}
+	return e.Exchange.GetKlinesContext(ctx, symbol, interval, limit)
+}
@@ -0,0 +1,222 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// List order statuses of an order list
+const (
+	ListOrderStatusExecuting = "EXECUTING" // legs are still working
+	ListOrderStatusAllDone   = "ALL_DONE"  // one leg filled or the list was cancelled
+	ListOrderStatusReject    = "REJECT"
+)
+
+// OCORequest describes a one-cancels-the-other bracket: a take-profit
+// LIMIT_MAKER leg and a STOP_LOSS_LIMIT leg on the same quantity
+type OCORequest struct {
+	Symbol            string
+	Side              string  // SELL protects a long position, BUY a short one
+	Quantity          float64 // base asset quantity for both legs
+	TakeProfitPrice   float64 // limit price of the take-profit leg
+	StopPrice         float64 // trigger price of the stop-loss leg
+	StopLimitPrice    float64 // limit price of the stop-loss leg once triggered
+	ListClientOrderID string  // listClientOrderId; derived from the order parameters when empty
+	TakeProfitOrderID string  // optional clientOrderId of the take-profit leg
+	StopOrderID       string  // optional clientOrderId of the stop-loss leg
+}
+
+// OrderList is the exchange's view of an OCO order list
+type OrderList struct {
+	OrderListID       int64
+	ContingencyType   string // OCO
+	ListStatusType    string // RESPONSE, EXEC_STARTED, ALL_DONE
+	ListOrderStatus   string // one of the ListOrderStatus constants
+	ListClientOrderID string
+	Symbol            string
+	TransactionTime   time.Time
+	OrderIDs          []int64        // exchange IDs of the legs
+	Orders            []*OrderResult // leg details, only on place/cancel responses
+}
+
+// Done reports whether the list no longer protects the position
+func (l *OrderList) Done() bool {
+	return l.ListOrderStatus != ListOrderStatusExecuting
+}
+
+// PlaceOCO places an OCO bracket order.
+// Quantity and prices are rounded to the symbol's stepSize/tickSize and both
+// legs are checked against its exchangeInfo filters before the list is sent.
+// Like CreateOrder, a lost response is resolved by ListClientOrderID.
+func (b *HttpRequest) PlaceOCO(req OCORequest) (*OrderList, error) {
+	return b.PlaceOCOContext(context.Background(), req)
+}
+
+// PlaceOCOContext is PlaceOCO with a context
+func (b *HttpRequest) PlaceOCOContext(ctx context.Context, req OCORequest) (*OrderList, error) {
+	params, err := b.ocoParams(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	listClientOrderID := b.ensureClientOrderID(ctx, params, "listClientOrderId")
+	body, err := b.submitOnce(ctx, "/api/v3/orderList/oco", req.Symbol, listClientOrderID, params, func() ([]byte, error) {
+		return b.lookupOrderList(ctx, listClientOrderID)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to place OCO order: %w", err)
+	}
+
+	list, err := parseOrderList(body)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Printf("✅ OCO placed: %s %s (List ID: %d, Status: %s)\n", req.Side, req.Symbol, list.OrderListID, list.ListOrderStatus)
+	return list, nil
+}
+
+// ocoParams validates req against the symbol filters and builds the request parameters
+func (b *HttpRequest) ocoParams(ctx context.Context, req OCORequest) (map[string]string, error) {
+	if req.TakeProfitPrice <= 0 || req.StopPrice <= 0 || req.StopLimitPrice <= 0 {
+		return nil, fmt.Errorf("OCO order for %s needs take-profit, stop and stop-limit prices", req.Symbol)
+	}
+
+	filters, err := b.getSymbolFilters(ctx, req.Symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to place OCO order: %w", err)
+	}
+
+	qty := filters.RoundQuantity(req.Quantity)
+	if err := filters.Validate(qty, filters.RoundPrice(req.TakeProfitPrice)); err != nil {
+		return nil, err
+	}
+	if err := filters.Validate(qty, filters.RoundPrice(req.StopLimitPrice)); err != nil {
+		return nil, err
+	}
+
+	takeProfit := map[string]string{"Type": OrderTypeLimitMaker, "Price": filters.FormatPrice(req.TakeProfitPrice)}
+	stopLoss := map[string]string{
+		"Type":        OrderTypeStopLossLimit,
+		"Price":       filters.FormatPrice(req.StopLimitPrice),
+		"StopPrice":   filters.FormatPrice(req.StopPrice),
+		"TimeInForce": TimeInForceGTC,
+	}
+	if req.TakeProfitOrderID != "" {
+		takeProfit["ClientOrderId"] = req.TakeProfitOrderID
+	}
+	if req.StopOrderID != "" {
+		stopLoss["ClientOrderId"] = req.StopOrderID
+	}
+
+	// the leg priced above the market is "above", the other "below"
+	above, below := takeProfit, stopLoss
+	switch req.Side {
+	case "SELL":
+		if req.TakeProfitPrice <= req.StopPrice {
+			return nil, fmt.Errorf("OCO sell for %s needs take-profit above stop price", req.Symbol)
+		}
+	case "BUY":
+		if req.TakeProfitPrice >= req.StopPrice {
+			return nil, fmt.Errorf("OCO buy for %s needs take-profit below stop price", req.Symbol)
+		}
+		above, below = stopLoss, takeProfit
+	default:
+		return nil, fmt.Errorf("unsupported OCO side %q", req.Side)
+	}
+
+	params := map[string]string{
+		"symbol":           req.Symbol,
+		"side":             req.Side,
+		"quantity":         filters.FormatQuantity(req.Quantity),
+		"newOrderRespType": "FULL",
+	}
+	if req.ListClientOrderID != "" {
+		params["listClientOrderId"] = req.ListClientOrderID
+	}
+	for k, v := range above {
+		params["above"+k] = v
+	}
+	for k, v := range below {
+		params["below"+k] = v
+	}
+	return params, nil
+}
+
+// GetOrderList returns the current status of an order list
+func (b *HttpRequest) GetOrderList(orderListID int64) (*OrderList, error) {
+	return b.GetOrderListContext(context.Background(), orderListID)
+}
+
+// GetOrderListContext is GetOrderList with a context
+func (b *HttpRequest) GetOrderListContext(ctx context.Context, orderListID int64) (*OrderList, error) {
+	body, err := b.SignedRequestContext(ctx, "GET", "/api/v3/orderList", map[string]string{
+		"orderListId": strconv.FormatInt(orderListID, 10),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query order list: %w", err)
+	}
+	return parseOrderList(body)
+}
+
+// CancelOrderList cancels both legs of an order list
+func (b *HttpRequest) CancelOrderList(symbol string, orderListID int64) (*OrderList, error) {
+	return b.CancelOrderListContext(context.Background(), symbol, orderListID)
+}
+
+// CancelOrderListContext is CancelOrderList with a context
+func (b *HttpRequest) CancelOrderListContext(ctx context.Context, symbol string, orderListID int64) (*OrderList, error) {
+	body, err := b.SignedRequestContext(ctx, "DELETE", "/api/v3/orderList", map[string]string{
+		"symbol":      symbol,
+		"orderListId": strconv.FormatInt(orderListID, 10),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel order list: %w", err)
+	}
+
+	list, err := parseOrderList(body)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Printf("🛑 OCO canceled: %s (List ID: %d, Status: %s)\n", symbol, list.OrderListID, list.ListOrderStatus)
+	return list, nil
+}
+
+// parseOrderList parses an order list response
+func parseOrderList(body []byte) (*OrderList, error) {
+	var raw struct {
+		OrderListID       int64  `json:"orderListId"`
+		ContingencyType   string `json:"contingencyType"`
+		ListStatusType    string `json:"listStatusType"`
+		ListOrderStatus   string `json:"listOrderStatus"`
+		ListClientOrderID string `json:"listClientOrderId"`
+		TransactionTime   int64  `json:"transactionTime"`
+		Symbol            string `json:"symbol"`
+		Orders            []struct {
+			OrderID int64 `json:"orderId"`
+		} `json:"orders"`
+		OrderReports []rawOrder `json:"orderReports"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse order list response: %w", err)
+	}
+
+	l := &OrderList{
+		OrderListID:       raw.OrderListID,
+		ContingencyType:   raw.ContingencyType,
+		ListStatusType:    raw.ListStatusType,
+		ListOrderStatus:   raw.ListOrderStatus,
+		ListClientOrderID: raw.ListClientOrderID,
+		Symbol:            raw.Symbol,
+		TransactionTime:   time.UnixMilli(raw.TransactionTime),
+	}
+	for _, o := range raw.Orders {
+		l.OrderIDs = append(l.OrderIDs, o.OrderID)
+	}
+	for i := range raw.OrderReports {
+		l.Orders = append(l.Orders, raw.OrderReports[i].result())
+	}
+	return l, nil
+}
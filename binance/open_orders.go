@@ -0,0 +1,184 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Cancel-replace modes
+const (
+	CancelReplaceStopOnFailure = "STOP_ON_FAILURE" // don't place the new order if the cancel fails
+	CancelReplaceAllowFailure  = "ALLOW_FAILURE"   // place the new order even if the cancel fails
+)
+
+// CancelReplaceResult is the outcome of a cancel-replace request
+type CancelReplaceResult struct {
+	CancelResult   string // SUCCESS, FAILURE or NOT_ATTEMPTED; empty when unknown
+	NewOrderResult string // SUCCESS, FAILURE or NOT_ATTEMPTED
+	Canceled       *OrderResult
+	Order          *OrderResult
+}
+
+// GetOpenOrders returns the resting orders on symbol, or on every symbol when symbol is empty
+func (b *HttpRequest) GetOpenOrders(symbol string) ([]*OrderResult, error) {
+	return b.GetOpenOrdersContext(context.Background(), symbol)
+}
+
+// GetOpenOrdersContext is GetOpenOrders with a context
+func (b *HttpRequest) GetOpenOrdersContext(ctx context.Context, symbol string) ([]*OrderResult, error) {
+	params := map[string]string{}
+	if symbol != "" {
+		params["symbol"] = symbol
+	}
+
+	body, err := b.SignedRequestContext(ctx, "GET", "/api/v3/openOrders", params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch open orders: %w", err)
+	}
+
+	var raw []rawOrder
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse open orders: %w", err)
+	}
+	orders := make([]*OrderResult, 0, len(raw))
+	for i := range raw {
+		orders = append(orders, raw[i].result())
+	}
+	return orders, nil
+}
+
+// GetOrder returns the current status of an order by its exchange order ID
+func (b *HttpRequest) GetOrder(symbol string, orderID int64) (*OrderResult, error) {
+	return b.GetOrderContext(context.Background(), symbol, orderID)
+}
+
+// GetOrderContext is GetOrder with a context
+func (b *HttpRequest) GetOrderContext(ctx context.Context, symbol string, orderID int64) (*OrderResult, error) {
+	body, err := b.SignedRequestContext(ctx, "GET", "/api/v3/order", map[string]string{
+		"symbol":  symbol,
+		"orderId": strconv.FormatInt(orderID, 10),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query order: %w", err)
+	}
+	return parseOrderResult(body)
+}
+
+// CancelOrder cancels an active order by its exchange order ID
+func (b *HttpRequest) CancelOrder(symbol string, orderID int64) (*OrderResult, error) {
+	return b.CancelOrderContext(context.Background(), symbol, orderID)
+}
+
+// CancelOrderContext is CancelOrder with a context
+func (b *HttpRequest) CancelOrderContext(ctx context.Context, symbol string, orderID int64) (*OrderResult, error) {
+	body, err := b.SignedRequestContext(ctx, "DELETE", "/api/v3/order", map[string]string{
+		"symbol":  symbol,
+		"orderId": strconv.FormatInt(orderID, 10),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel order: %w", err)
+	}
+
+	result, err := parseOrderResult(body)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Printf("🛑 Order canceled: %s (ID: %d, Status: %s)\n", symbol, result.OrderID, result.Status)
+	return result, nil
+}
+
+// CancelAllOrders cancels every resting order on symbol, including the legs
+// of order lists, and returns the canceled orders
+func (b *HttpRequest) CancelAllOrders(symbol string) ([]*OrderResult, error) {
+	return b.CancelAllOrdersContext(context.Background(), symbol)
+}
+
+// CancelAllOrdersContext is CancelAllOrders with a context
+func (b *HttpRequest) CancelAllOrdersContext(ctx context.Context, symbol string) ([]*OrderResult, error) {
+	body, err := b.SignedRequestContext(ctx, "DELETE", "/api/v3/openOrders", map[string]string{"symbol": symbol})
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel open orders: %w", err)
+	}
+
+	// plain orders and order lists come back mixed in one array
+	var raw []struct {
+		rawOrder
+		OrderReports []rawOrder `json:"orderReports"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse canceled orders: %w", err)
+	}
+
+	var orders []*OrderResult
+	for i := range raw {
+		if len(raw[i].OrderReports) == 0 {
+			orders = append(orders, raw[i].rawOrder.result())
+			continue
+		}
+		for j := range raw[i].OrderReports {
+			orders = append(orders, raw[i].OrderReports[j].result())
+		}
+	}
+	fmt.Printf("🛑 Open orders canceled: %s (%d orders)\n", symbol, len(orders))
+	return orders, nil
+}
+
+// CancelReplace cancels orderID and places req in a single request. With
+// CancelReplaceStopOnFailure the new order is only placed if the cancel succeeds.
+// Partial failures are returned as an *APIError matching ErrCancelReplaceFailed
+// or ErrCancelReplacePartiallyFailed. Like CreateOrder it is never resent: an
+// *OrderUnknownError means neither the response nor a lookup of the new order
+// told whether it was placed. If the lookup finds it, CancelResult is empty.
+func (b *HttpRequest) CancelReplace(orderID int64, req OrderRequest, mode string) (*CancelReplaceResult, error) {
+	return b.CancelReplaceContext(context.Background(), orderID, req, mode)
+}
+
+// CancelReplaceContext is CancelReplace with a context
+func (b *HttpRequest) CancelReplaceContext(ctx context.Context, orderID int64, req OrderRequest, mode string) (*CancelReplaceResult, error) {
+	params, err := b.orderParams(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if mode == "" {
+		mode = CancelReplaceStopOnFailure
+	}
+	params["cancelReplaceMode"] = mode
+	params["cancelOrderId"] = strconv.FormatInt(orderID, 10)
+	clientOrderID := b.ensureClientOrderID(ctx, params, "newClientOrderId")
+
+	// when the outcome is unknown only the new order can be looked up; if it
+	// exists it was placed, and what happened to the cancel is left unknown
+	body, err := b.submitOnce(ctx, "/api/v3/order/cancelReplace", req.Symbol, clientOrderID, params, func() ([]byte, error) {
+		order, err := b.lookupOrder(ctx, req.Symbol, clientOrderID)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(`{"newOrderResult":"SUCCESS","newOrderResponse":` + string(order) + `}`), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel-replace order: %w", err)
+	}
+
+	var raw struct {
+		CancelResult     string    `json:"cancelResult"`
+		NewOrderResult   string    `json:"newOrderResult"`
+		CancelResponse   *rawOrder `json:"cancelResponse"`
+		NewOrderResponse *rawOrder `json:"newOrderResponse"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse cancel-replace response: %w", err)
+	}
+
+	result := &CancelReplaceResult{CancelResult: raw.CancelResult, NewOrderResult: raw.NewOrderResult}
+	if raw.CancelResponse != nil {
+		result.Canceled = raw.CancelResponse.result()
+	}
+	if raw.NewOrderResponse != nil {
+		result.Order = raw.NewOrderResponse.result()
+		fmt.Printf("🔁 Order replaced: %s %s %s (ID: %d → %d, Status: %s)\n",
+			req.Type, req.Side, req.Symbol, orderID, result.Order.OrderID, result.Order.Status)
+	}
+	return result, nil
+}
@@ -0,0 +1,291 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Order types supported by CreateOrder
+const (
+	OrderTypeMarket          = "MARKET"
+	OrderTypeLimit           = "LIMIT"
+	OrderTypeLimitMaker      = "LIMIT_MAKER"
+	OrderTypeStopLossLimit   = "STOP_LOSS_LIMIT"
+	OrderTypeTakeProfitLimit = "TAKE_PROFIT_LIMIT"
+)
+
+// Time in force values for limit orders
+const (
+	TimeInForceGTC = "GTC" // good till cancelled
+	TimeInForceIOC = "IOC" // immediate or cancel
+	TimeInForceFOK = "FOK" // fill or kill
+)
+
+// OrderRequest describes a new order
+type OrderRequest struct {
+	Symbol        string
+	Side          string  // BUY or SELL
+	Type          string  // one of the OrderType constants
+	Quantity      float64 // base asset quantity
+	QuoteOrderQty float64 // quote amount to spend or receive instead of Quantity, MARKET only
+	Price         float64 // limit price, unused for MARKET
+	StopPrice     float64 // trigger price for STOP_LOSS_LIMIT / TAKE_PROFIT_LIMIT
+	TimeInForce   string  // defaults to GTC for limit orders, unused for MARKET / LIMIT_MAKER
+	ClientOrderID string  // newClientOrderId; derived from the order parameters when empty
+	Validate      bool    // run it through TestOrder first and only place it if that passes
+}
+
+// Fill is a single trade that (partially) executed an order
+type Fill struct {
+	TradeID         int64
+	Price           float64
+	Qty             float64
+	Commission      float64
+	CommissionAsset string
+}
+
+// OrderResult is the exchange's view of an order
+type OrderResult struct {
+	Symbol              string
+	OrderID             int64
+	ClientOrderID       string
+	Side                string
+	Type                string
+	Status              string // NEW, PARTIALLY_FILLED, FILLED, CANCELED, ...
+	TimeInForce         string
+	Price               float64
+	StopPrice           float64
+	OrigQty             float64
+	ExecutedQty         float64
+	CummulativeQuoteQty float64
+	OrderListID         int64     // order list (e.g. OCO) the order belongs to, -1 if none
+	TransactTime        time.Time // when the order was created
+	UpdateTime          time.Time // last status change, only on queried orders
+	Fills               []Fill
+	Commission          *OrderCommission // rates from TestOrder, when the order was validated first
+}
+
+// AveragePrice returns the average fill price, or 0 if nothing executed
+func (r *OrderResult) AveragePrice() float64 {
+	if r.ExecutedQty == 0 {
+		return 0
+	}
+	return r.CummulativeQuoteQty / r.ExecutedQty
+}
+
+// PlaceOrder places a market buy/sell order.
+// The quantity is rounded to the symbol's stepSize and checked against its
+// exchangeInfo filters; a *FilterError is returned without sending the order.
+func (b *HttpRequest) PlaceOrder(symbol, side string, quantity float64) (*OrderResult, error) {
+	return b.PlaceOrderContext(context.Background(), symbol, side, quantity)
+}
+
+// PlaceOrderContext is PlaceOrder with a context
+func (b *HttpRequest) PlaceOrderContext(ctx context.Context, symbol, side string, quantity float64) (*OrderResult, error) {
+	return b.CreateOrderContext(ctx, OrderRequest{
+		Symbol:   symbol,
+		Side:     side,
+		Type:     OrderTypeMarket,
+		Quantity: quantity,
+	})
+}
+
+// PlaceQuoteOrder places a market buy/sell order for quoteQty of the quote
+// asset (e.g. spend 20 USDT) using quoteOrderQty; the exchange works out the
+// base quantity. The amount is checked against the symbol's notional filter.
+func (b *HttpRequest) PlaceQuoteOrder(symbol, side string, quoteQty float64) (*OrderResult, error) {
+	return b.PlaceQuoteOrderContext(context.Background(), symbol, side, quoteQty)
+}
+
+// PlaceQuoteOrderContext is PlaceQuoteOrder with a context
+func (b *HttpRequest) PlaceQuoteOrderContext(ctx context.Context, symbol, side string, quoteQty float64) (*OrderResult, error) {
+	return b.CreateOrderContext(ctx, OrderRequest{
+		Symbol:        symbol,
+		Side:          side,
+		Type:          OrderTypeMarket,
+		QuoteOrderQty: quoteQty,
+	})
+}
+
+// CreateOrder places an order of any supported type.
+// Quantity and prices are rounded to the symbol's stepSize/tickSize and
+// checked against its exchangeInfo filters before the order is sent.
+// Orders without a ClientOrderID get a deterministic one; if the response is
+// lost the order is looked up by that ID rather than blindly resubmitted.
+func (b *HttpRequest) CreateOrder(req OrderRequest) (*OrderResult, error) {
+	return b.CreateOrderContext(context.Background(), req)
+}
+
+// CreateOrderContext is CreateOrder with a context
+func (b *HttpRequest) CreateOrderContext(ctx context.Context, req OrderRequest) (*OrderResult, error) {
+	params, err := b.orderParams(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var commission *OrderCommission
+	if req.Validate {
+		if commission, err = b.TestOrderContext(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+
+	clientOrderID := b.ensureClientOrderID(ctx, params, "newClientOrderId")
+	body, err := b.submitOnce(ctx, "/api/v3/order", req.Symbol, clientOrderID, params, func() ([]byte, error) {
+		return b.lookupOrder(ctx, req.Symbol, clientOrderID)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to place order: %w", err)
+	}
+
+	result, err := parseOrderResult(body)
+	if err != nil {
+		return nil, err
+	}
+	result.Commission = commission
+	fmt.Printf("✅ Order placed: %s %s %s (ID: %d, Status: %s)\n", req.Type, req.Side, req.Symbol, result.OrderID, result.Status)
+	return result, nil
+}
+
+// orderParams validates req against the symbol filters and builds the request parameters
+func (b *HttpRequest) orderParams(ctx context.Context, req OrderRequest) (map[string]string, error) {
+	filters, err := b.getSymbolFilters(ctx, req.Symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to place order: %w", err)
+	}
+
+	params := map[string]string{
+		"symbol":           req.Symbol,
+		"side":             req.Side,
+		"type":             req.Type,
+		"newOrderRespType": "FULL",
+	}
+	if req.ClientOrderID != "" {
+		params["newClientOrderId"] = req.ClientOrderID
+	}
+
+	if req.QuoteOrderQty > 0 {
+		if req.Type != OrderTypeMarket {
+			return nil, fmt.Errorf("quoteOrderQty is only supported for MARKET orders, not %s", req.Type)
+		}
+		price, err := b.GetPriceContext(ctx, req.Symbol)
+		if err != nil {
+			return nil, fmt.Errorf("failed to place order: %w", err)
+		}
+		// the exchange rounds the resulting quantity itself
+		if err := filters.Validate(req.QuoteOrderQty/price, price); err != nil {
+			return nil, err
+		}
+		params["quoteOrderQty"] = filters.FormatQuoteQty(req.QuoteOrderQty)
+		return params, nil
+	}
+	params["quantity"] = filters.FormatQuantity(req.Quantity)
+
+	// the price the notional filter is checked against
+	price := req.Price
+	switch req.Type {
+	case OrderTypeMarket:
+		if price, err = b.GetPriceContext(ctx, req.Symbol); err != nil {
+			return nil, fmt.Errorf("failed to place order: %w", err)
+		}
+	case OrderTypeLimit, OrderTypeLimitMaker, OrderTypeStopLossLimit, OrderTypeTakeProfitLimit:
+		if req.Price <= 0 {
+			return nil, fmt.Errorf("%s order for %s needs a price", req.Type, req.Symbol)
+		}
+		price = filters.RoundPrice(req.Price)
+		params["price"] = filters.FormatPrice(req.Price)
+		if req.Type != OrderTypeLimitMaker {
+			params["timeInForce"] = req.TimeInForce
+			if req.TimeInForce == "" {
+				params["timeInForce"] = TimeInForceGTC
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unsupported order type %q", req.Type)
+	}
+
+	if req.Type == OrderTypeStopLossLimit || req.Type == OrderTypeTakeProfitLimit {
+		if req.StopPrice <= 0 {
+			return nil, fmt.Errorf("%s order for %s needs a stop price", req.Type, req.Symbol)
+		}
+		params["stopPrice"] = filters.FormatPrice(req.StopPrice)
+	}
+
+	if err := filters.Validate(filters.RoundQuantity(req.Quantity), price); err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// rawOrder is the JSON shape of an order in Binance responses
+type rawOrder struct {
+	Symbol              string `json:"symbol"`
+	OrderID             int64  `json:"orderId"`
+	ClientOrderID       string `json:"clientOrderId"`
+	OrderListID         int64  `json:"orderListId"`
+	TransactTime        int64  `json:"transactTime"`
+	Time                int64  `json:"time"` // creation time on queried orders
+	UpdateTime          int64  `json:"updateTime"`
+	Price               string `json:"price"`
+	StopPrice           string `json:"stopPrice"`
+	OrigQty             string `json:"origQty"`
+	ExecutedQty         string `json:"executedQty"`
+	CummulativeQuoteQty string `json:"cummulativeQuoteQty"`
+	Status              string `json:"status"`
+	TimeInForce         string `json:"timeInForce"`
+	Type                string `json:"type"`
+	Side                string `json:"side"`
+	Fills               []struct {
+		TradeID         int64  `json:"tradeId"`
+		Price           string `json:"price"`
+		Qty             string `json:"qty"`
+		Commission      string `json:"commission"`
+		CommissionAsset string `json:"commissionAsset"`
+	} `json:"fills"`
+}
+
+// result converts the raw order into an OrderResult
+func (raw *rawOrder) result() *OrderResult {
+	r := &OrderResult{
+		Symbol:        raw.Symbol,
+		OrderID:       raw.OrderID,
+		ClientOrderID: raw.ClientOrderID,
+		Side:          raw.Side,
+		Type:          raw.Type,
+		Status:        raw.Status,
+		TimeInForce:   raw.TimeInForce,
+		OrderListID:   raw.OrderListID,
+		TransactTime:  time.UnixMilli(raw.TransactTime),
+	}
+	if raw.TransactTime == 0 {
+		r.TransactTime = time.UnixMilli(raw.Time)
+	}
+	if raw.UpdateTime != 0 {
+		r.UpdateTime = time.UnixMilli(raw.UpdateTime)
+	}
+	r.Price, _ = strconv.ParseFloat(raw.Price, 64)
+	r.StopPrice, _ = strconv.ParseFloat(raw.StopPrice, 64)
+	r.OrigQty, _ = strconv.ParseFloat(raw.OrigQty, 64)
+	r.ExecutedQty, _ = strconv.ParseFloat(raw.ExecutedQty, 64)
+	r.CummulativeQuoteQty, _ = strconv.ParseFloat(raw.CummulativeQuoteQty, 64)
+	for _, f := range raw.Fills {
+		fill := Fill{TradeID: f.TradeID, CommissionAsset: f.CommissionAsset}
+		fill.Price, _ = strconv.ParseFloat(f.Price, 64)
+		fill.Qty, _ = strconv.ParseFloat(f.Qty, 64)
+		fill.Commission, _ = strconv.ParseFloat(f.Commission, 64)
+		r.Fills = append(r.Fills, fill)
+	}
+	return r
+}
+
+// parseOrderResult parses an order response (ACK, RESULT or FULL)
+func parseOrderResult(body []byte) (*OrderResult, error) {
+	var raw rawOrder
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse order response: %w", err)
+	}
+	return raw.result(), nil
+}
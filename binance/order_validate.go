@@ -0,0 +1,80 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// OrderCommission holds the commission rates Binance would charge for an order
+type OrderCommission struct {
+	Maker         float64 // standard maker rate, e.g. 0.001
+	Taker         float64 // standard taker rate
+	TaxMaker      float64
+	TaxTaker      float64
+	Discount      float64 // share of the standard rate saved when paying in DiscountAsset
+	DiscountAsset string  // usually BNB
+}
+
+// Estimate returns the commission on notional in the quote asset, without
+// the DiscountAsset discount
+func (c *OrderCommission) Estimate(notional float64, maker bool) float64 {
+	if maker {
+		return notional * (c.Maker + c.TaxMaker)
+	}
+	return notional * (c.Taker + c.TaxTaker)
+}
+
+// TestOrder sends req to /api/v3/order/test: Binance runs the same filter,
+// balance and permission checks as for a real order but doesn't place it.
+// The commission rates that would apply are returned.
+func (b *HttpRequest) TestOrder(req OrderRequest) (*OrderCommission, error) {
+	return b.TestOrderContext(context.Background(), req)
+}
+
+// TestOrderContext is TestOrder with a context
+func (b *HttpRequest) TestOrderContext(ctx context.Context, req OrderRequest) (*OrderCommission, error) {
+	params, err := b.orderParams(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	params["computeCommissionRates"] = "true"
+
+	body, err := b.SignedRequestContext(ctx, "POST", "/api/v3/order/test", params)
+	if err != nil {
+		return nil, fmt.Errorf("order validation failed: %w", err)
+	}
+
+	var raw struct {
+		Standard struct {
+			Maker string `json:"maker"`
+			Taker string `json:"taker"`
+		} `json:"standardCommissionForOrder"`
+		Tax struct {
+			Maker string `json:"maker"`
+			Taker string `json:"taker"`
+		} `json:"taxCommissionForOrder"`
+		Discount struct {
+			EnabledForAccount bool   `json:"enabledForAccount"`
+			EnabledForSymbol  bool   `json:"enabledForSymbol"`
+			DiscountAsset     string `json:"discountAsset"`
+			Discount          string `json:"discount"`
+		} `json:"discount"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse order validation response: %w", err)
+	}
+
+	c := &OrderCommission{}
+	c.Maker, _ = strconv.ParseFloat(raw.Standard.Maker, 64)
+	c.Taker, _ = strconv.ParseFloat(raw.Standard.Taker, 64)
+	c.TaxMaker, _ = strconv.ParseFloat(raw.Tax.Maker, 64)
+	c.TaxTaker, _ = strconv.ParseFloat(raw.Tax.Taker, 64)
+	if raw.Discount.EnabledForAccount && raw.Discount.EnabledForSymbol {
+		c.Discount, _ = strconv.ParseFloat(raw.Discount.Discount, 64)
+		c.DiscountAsset = raw.Discount.DiscountAsset
+	}
+	fmt.Printf("🧪 Order validated: %s %s %s (taker fee %.4f%%)\n", req.Type, req.Side, req.Symbol, (c.Taker+c.TaxTaker)*100)
+	return c, nil
+}
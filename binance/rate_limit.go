@@ -0,0 +1,212 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Binance spot default limits; see GET /api/v3/exchangeInfo rateLimits
+const (
+	defaultWeightLimit     = 6000 // REQUEST_WEIGHT per minute
+	defaultOrderLimit10s   = 100  // ORDERS per 10 seconds
+	defaultWeightSafetyPct = 0.9  // start waiting once 90% of the budget is used
+	maxRateLimitWait       = 2 * time.Minute
+)
+
+// RateLimitUsage is a snapshot of the latest usage reported by Binance
+type RateLimitUsage struct {
+	UsedWeight1m  int
+	OrderCount10s int
+	OrderCount1d  int
+	BannedUntil   time.Time
+}
+
+// requestWeight returns the REQUEST_WEIGHT Binance charges for a request,
+// see the weight of each endpoint in the spot API docs. /sapi endpoints
+// count towards separate limits and cost nothing here.
+func requestWeight(method, endpoint string, params map[string]string) int {
+	if !strings.HasPrefix(endpoint, "/api/") {
+		return 0
+	}
+	switch endpoint {
+	case "/api/v3/account", "/api/v3/myTrades", "/api/v3/exchangeInfo":
+		return 20
+	case "/api/v3/klines", "/api/v3/userDataStream":
+		return 2
+	case "/api/v3/openOrders":
+		if method == "GET" && params["symbol"] == "" {
+			return 80
+		}
+		if method == "GET" {
+			return 6
+		}
+	case "/api/v3/order", "/api/v3/orderList":
+		if method == "GET" {
+			return 4
+		}
+	case "/api/v3/order/test":
+		if params["computeCommissionRates"] == "true" {
+			return 20
+		}
+	case "/api/v3/ticker/price":
+		if params["symbol"] != "" {
+			return 2
+		}
+		return 4
+	case "/api/v3/ticker/24hr":
+		if params["symbol"] != "" {
+			return 2
+		}
+		symbols := strings.Count(params["symbols"], ",") + 1
+		switch {
+		case params["symbols"] == "" || symbols > 100:
+			return 80
+		case symbols > 20:
+			return 40
+		}
+		return 2
+	}
+	return 1
+}
+
+// rateLimiter keeps a local request weight budget: the weight of every
+// request is added before it is sent and resynced from the X-MBX-* usage
+// headers of each response. Requests that would exceed the budget are delayed.
+type rateLimiter struct {
+	mu            sync.Mutex
+	weightLimit   int
+	orderLimit10s int
+
+	usedWeight    int
+	weightAt      time.Time // when usedWeight was last counted or reported
+	pending       int       // weight of requests sent but not answered yet
+	orderCount10s int
+	orderCount1d  int
+	orderAt       time.Time
+	bannedUntil   time.Time // set from Retry-After on 429/418
+}
+
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{
+		weightLimit:   defaultWeightLimit,
+		orderLimit10s: defaultOrderLimit10s,
+	}
+}
+
+// wait blocks until the local budget allows a request of weight and then
+// reserves it; every successful wait must be followed by update or release.
+// Longer bans (usually a 418 IP ban) fail fast instead of stalling the caller.
+func (r *rateLimiter) wait(ctx context.Context, weight int, isOrder bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	for {
+		d := r.delay(weight, isOrder, time.Now())
+		if d <= 0 {
+			return nil
+		}
+		if d > maxRateLimitWait {
+			return fmt.Errorf("Binance rate limit: requests blocked for another %s", d.Round(time.Second))
+		}
+		log.Printf("⏳ Binance rate limit: waiting %s\n", d.Round(time.Millisecond))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(d):
+		}
+	}
+}
+
+// delay returns how long to wait before a request of weight fits the budget,
+// reserving it when it fits right away
+func (r *rateLimiter) delay(weight int, isOrder bool, now time.Time) time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if now.Before(r.bannedUntil) {
+		return r.bannedUntil.Sub(now)
+	}
+
+	// weight is counted per calendar minute
+	if now.Truncate(time.Minute).After(r.weightAt.Truncate(time.Minute)) {
+		r.usedWeight = 0
+	}
+	// a request heavier than the whole margin still goes out on a fresh minute
+	used := r.usedWeight + r.pending
+	if used > 0 && used+weight > int(float64(r.weightLimit)*defaultWeightSafetyPct) {
+		return now.Truncate(time.Minute).Add(time.Minute).Sub(now)
+	}
+
+	if isOrder {
+		window := now.Truncate(10 * time.Second)
+		if window.After(r.orderAt.Truncate(10 * time.Second)) {
+			r.orderCount10s = 0
+		}
+		if r.orderCount10s >= r.orderLimit10s {
+			return window.Add(10 * time.Second).Sub(now)
+		}
+		r.orderCount10s++
+		r.orderAt = now
+	}
+	r.pending += weight
+	return 0
+}
+
+// release books the weight reserved by wait for a request that got no
+// response; Binance may still have counted it
+func (r *rateLimiter) release(weight int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pending -= weight
+	r.usedWeight += weight
+	r.weightAt = time.Now()
+}
+
+// update settles the weight reserved by wait and resyncs with the usage
+// headers of the response
+func (r *rateLimiter) update(resp *http.Response, weight int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	r.pending -= weight
+	if v, err := strconv.Atoi(resp.Header.Get("X-MBX-USED-WEIGHT-1M")); err == nil {
+		r.usedWeight = v
+	} else {
+		r.usedWeight += weight
+	}
+	r.weightAt = now
+	if v, err := strconv.Atoi(resp.Header.Get("X-MBX-ORDER-COUNT-10S")); err == nil {
+		r.orderCount10s = v
+		r.orderAt = now
+	}
+	if v, err := strconv.Atoi(resp.Header.Get("X-MBX-ORDER-COUNT-1D")); err == nil {
+		r.orderCount1d = v
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusTeapot {
+		retryAfter := time.Minute
+		if v, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && v > 0 {
+			retryAfter = time.Duration(v) * time.Second
+		}
+		r.bannedUntil = now.Add(retryAfter)
+		log.Printf("⚠️  Binance returned %d, backing off for %s\n", resp.StatusCode, retryAfter)
+	}
+}
+
+func (r *rateLimiter) usage() RateLimitUsage {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return RateLimitUsage{
+		UsedWeight1m:  r.usedWeight,
+		OrderCount10s: r.orderCount10s,
+		OrderCount1d:  r.orderCount1d,
+		BannedUntil:   r.bannedUntil,
+	}
+}
@@ -0,0 +1,56 @@
+package binance
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterCountsWeightBeforeSending(t *testing.T) {
+	r := newRateLimiter()
+	now := time.Now().Truncate(time.Minute).Add(time.Second)
+	budget := int(float64(r.weightLimit) * defaultWeightSafetyPct)
+
+	// requests between responses are counted locally
+	for sent := 0; sent+20 <= budget; sent += 20 {
+		if d := r.delay(20, false, now); d != 0 {
+			t.Fatalf("delayed after %d weight of %d", sent, budget)
+		}
+	}
+	if d := r.delay(20, false, now); d <= 0 {
+		t.Fatalf("not delayed with %d weight pending", r.pending)
+	}
+
+	// a response resyncs the budget from the header
+	resp := &http.Response{Header: http.Header{"X-Mbx-Used-Weight-1m": []string{"100"}}}
+	r.update(resp, r.pending)
+	if r.pending != 0 || r.usedWeight != 100 {
+		t.Fatalf("after update pending=%d used=%d, want 0 and 100", r.pending, r.usedWeight)
+	}
+	if d := r.delay(20, false, time.Now()); d != 0 {
+		t.Fatalf("delayed after resync: %s", d)
+	}
+}
+
+func TestRequestWeight(t *testing.T) {
+	tests := []struct {
+		method, endpoint string
+		params           map[string]string
+		want             int
+	}{
+		{"GET", "/api/v3/account", nil, 20},
+		{"GET", "/api/v3/klines", map[string]string{"symbol": "BTCUSDT"}, 2},
+		{"GET", "/api/v3/openOrders", nil, 80},
+		{"GET", "/api/v3/openOrders", map[string]string{"symbol": "BTCUSDT"}, 6},
+		{"POST", "/api/v3/order", nil, 1},
+		{"GET", "/api/v3/order", nil, 4},
+		{"POST", "/api/v3/order/test", map[string]string{"computeCommissionRates": "true"}, 20},
+		{"GET", "/api/v3/ticker/price", map[string]string{"symbols": `["A","B"]`}, 4},
+		{"GET", "/sapi/v1/asset/dribblet", nil, 0},
+	}
+	for _, tt := range tests {
+		if got := requestWeight(tt.method, tt.endpoint, tt.params); got != tt.want {
+			t.Errorf("requestWeight(%s %s %v) = %d, want %d", tt.method, tt.endpoint, tt.params, got, tt.want)
+		}
+	}
+}
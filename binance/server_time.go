@@ -0,0 +1,85 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+)
+
+// defaultTimeSyncInterval is how often signed requests re-sync with /api/v3/time
+const defaultTimeSyncInterval = 30 * time.Minute
+
+// GetServerTime returns the current Binance server time
+func (b *HttpRequest) GetServerTime() (time.Time, error) {
+	return b.getServerTime(context.Background())
+}
+
+func (b *HttpRequest) getServerTime(ctx context.Context) (time.Time, error) {
+	body, err := b.PublicRequestContext(ctx, "/api/v3/time", nil)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var result struct {
+		ServerTime int64 `json:"serverTime"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse server time: %w", err)
+	}
+	return time.UnixMilli(result.ServerTime), nil
+}
+
+// SyncTime measures the offset between the local clock and Binance server
+// time; the offset is applied to the timestamp of every signed request.
+func (b *HttpRequest) SyncTime() error {
+	return b.syncTime(context.Background())
+}
+
+func (b *HttpRequest) syncTime(ctx context.Context) error {
+	start := time.Now()
+	serverTime, err := b.getServerTime(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to sync server time: %w", err)
+	}
+	end := time.Now()
+
+	// assume the server stamped the response half way through the round trip
+	local := start.Add(end.Sub(start) / 2)
+	offset := serverTime.Sub(local)
+
+	b.timeMu.Lock()
+	b.timeOffset = offset
+	b.lastTimeSync = end
+	b.timeMu.Unlock()
+
+	if offset.Abs() >= time.Second {
+		log.Printf("⚠️  Local clock drift vs Binance: %s\n", offset.Round(time.Millisecond))
+	} else {
+		log.Printf("🕒 Binance time synced, drift: %s\n", offset.Round(time.Millisecond))
+	}
+	return nil
+}
+
+// TimeOffset returns the last measured server time minus local time
+func (b *HttpRequest) TimeOffset() time.Duration {
+	b.timeMu.Lock()
+	defer b.timeMu.Unlock()
+	return b.timeOffset
+}
+
+// serverNow returns the local time corrected by the measured offset,
+// re-syncing first when the last sync is older than TimeSyncInterval.
+func (b *HttpRequest) serverNow(ctx context.Context) time.Time {
+	b.timeMu.Lock()
+	stale := b.TimeSyncInterval > 0 && time.Since(b.lastTimeSync) > b.TimeSyncInterval
+	b.timeMu.Unlock()
+
+	if stale {
+		if err := b.syncTime(ctx); err != nil {
+			log.Println(err)
+		}
+	}
+	return time.Now().Add(b.TimeOffset())
+}
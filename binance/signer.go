@@ -0,0 +1,125 @@
+package binance
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Key types accepted by LoadSigner
+const (
+	KeyTypeHMAC    = "HMAC"
+	KeyTypeRSA     = "RSA"
+	KeyTypeEd25519 = "ED25519"
+)
+
+// Signer signs the query string of signed requests
+type Signer interface {
+	Sign(payload string) (string, error)
+}
+
+// HMACSigner signs with HMAC-SHA256 and a secret shared with Binance
+type HMACSigner struct {
+	secret []byte
+}
+
+// NewHMACSigner creates a signer for an HMAC secret key
+func NewHMACSigner(secretKey string) *HMACSigner {
+	return &HMACSigner{secret: []byte(secretKey)}
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of payload
+func (s *HMACSigner) Sign(payload string) (string, error) {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// RSASigner signs with RSASSA-PKCS1-v1_5 over SHA-256; only the public key is registered with Binance
+type RSASigner struct {
+	key *rsa.PrivateKey
+}
+
+// Sign returns the base64 encoded RSA signature of payload
+func (s *RSASigner) Sign(payload string) (string, error) {
+	digest := sha256.Sum256([]byte(payload))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign request: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(sig), nil
+}
+
+// Ed25519Signer signs with an Ed25519 key; only the public key is registered with Binance
+type Ed25519Signer struct {
+	key ed25519.PrivateKey
+}
+
+// Sign returns the base64 encoded Ed25519 signature of payload
+func (s *Ed25519Signer) Sign(payload string) (string, error) {
+	return base64.StdEncoding.EncodeToString(ed25519.Sign(s.key, []byte(payload))), nil
+}
+
+// LoadSigner creates the signer for keyType. For HMAC secret is the shared
+// secret key; for RSA and ED25519 it is the path of a PEM encoded private key.
+func LoadSigner(keyType, secret string) (Signer, error) {
+	switch strings.ToUpper(keyType) {
+	case "", KeyTypeHMAC:
+		if secret == "" {
+			return nil, errors.New("HMAC signing needs a secret key")
+		}
+		return NewHMACSigner(secret), nil
+	case KeyTypeRSA, KeyTypeEd25519:
+	default:
+		return nil, fmt.Errorf("unknown key type %q (HMAC, RSA, ED25519)", keyType)
+	}
+
+	key, err := loadPrivateKey(secret)
+	if err != nil {
+		return nil, err
+	}
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		if !strings.EqualFold(keyType, KeyTypeRSA) {
+			return nil, fmt.Errorf("%s holds an RSA key, not %s", secret, keyType)
+		}
+		return &RSASigner{key: k}, nil
+	case ed25519.PrivateKey:
+		if !strings.EqualFold(keyType, KeyTypeEd25519) {
+			return nil, fmt.Errorf("%s holds an Ed25519 key, not %s", secret, keyType)
+		}
+		return &Ed25519Signer{key: k}, nil
+	}
+	return nil, fmt.Errorf("%s: unsupported private key type %T", secret, key)
+}
+
+// loadPrivateKey reads a PKCS#8 (or PKCS#1 RSA) private key from a PEM file
+func loadPrivateKey(path string) (any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read private key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s: no PEM data found", path)
+	}
+	if block.Type == "RSA PRIVATE KEY" {
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to parse private key: %w", path, err)
+	}
+	return key, nil
+}
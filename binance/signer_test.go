@@ -0,0 +1,173 @@
+package binance
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const signerPayload = "symbol=BTCUSDT&side=SELL&type=LIMIT&quantity=1&price=9000&timestamp=1499827319559"
+
+// writePEM writes der as a PEM block of blockType to a file in a temp dir
+func writePEM(t *testing.T, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "key.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func mustPKCS8(t *testing.T, key any) []byte {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return der
+}
+
+func TestLoadSigner(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edPub, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifyRSA := func(t *testing.T, sig string) {
+		raw, err := base64.StdEncoding.DecodeString(sig)
+		if err != nil {
+			t.Fatalf("signature %q is not base64: %v", sig, err)
+		}
+		digest := sha256.Sum256([]byte(signerPayload))
+		if err := rsa.VerifyPKCS1v15(&rsaKey.PublicKey, crypto.SHA256, digest[:], raw); err != nil {
+			t.Fatalf("RSA signature does not verify: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		keyType string
+		secret  func(t *testing.T) string
+		want    string // signer type
+		verify  func(t *testing.T, sig string)
+	}{
+		{
+			name:    "HMAC",
+			keyType: "",
+			secret:  func(t *testing.T) string { return "shared-secret" },
+			want:    "*binance.HMACSigner",
+			verify: func(t *testing.T, sig string) {
+				mac := hmac.New(sha256.New, []byte("shared-secret"))
+				mac.Write([]byte(signerPayload))
+				if want := hex.EncodeToString(mac.Sum(nil)); sig != want {
+					t.Fatalf("signature = %q, want hex %q", sig, want)
+				}
+			},
+		},
+		{
+			name:    "RSA PKCS#1",
+			keyType: "rsa",
+			secret: func(t *testing.T) string {
+				return writePEM(t, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey))
+			},
+			want:   "*binance.RSASigner",
+			verify: verifyRSA,
+		},
+		{
+			name:    "RSA PKCS#8",
+			keyType: KeyTypeRSA,
+			secret:  func(t *testing.T) string { return writePEM(t, "PRIVATE KEY", mustPKCS8(t, rsaKey)) },
+			want:    "*binance.RSASigner",
+			verify:  verifyRSA,
+		},
+		{
+			name:    "Ed25519 PKCS#8",
+			keyType: KeyTypeEd25519,
+			secret:  func(t *testing.T) string { return writePEM(t, "PRIVATE KEY", mustPKCS8(t, edKey)) },
+			want:    "*binance.Ed25519Signer",
+			verify: func(t *testing.T, sig string) {
+				raw, err := base64.StdEncoding.DecodeString(sig)
+				if err != nil {
+					t.Fatalf("signature %q is not base64: %v", sig, err)
+				}
+				if !ed25519.Verify(edPub, []byte(signerPayload), raw) {
+					t.Fatal("Ed25519 signature does not verify")
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signer, err := LoadSigner(tt.keyType, tt.secret(t))
+			if err != nil {
+				t.Fatalf("LoadSigner: %v", err)
+			}
+			if got := fmt.Sprintf("%T", signer); got != tt.want {
+				t.Fatalf("signer = %s, want %s", got, tt.want)
+			}
+
+			sig, err := signer.Sign(signerPayload)
+			if err != nil {
+				t.Fatalf("Sign: %v", err)
+			}
+			tt.verify(t, sig)
+		})
+	}
+}
+
+func TestLoadSignerErrors(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaPath := writePEM(t, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey))
+	edPath := writePEM(t, "PRIVATE KEY", mustPKCS8(t, edKey))
+	notPEM := filepath.Join(t.TempDir(), "key.txt")
+	if err := os.WriteFile(notPEM, []byte("not a key"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name, keyType, secret, wantErr string
+	}{
+		{"empty HMAC secret", KeyTypeHMAC, "", "needs a secret key"},
+		{"unknown key type", "DSA", rsaPath, "unknown key type"},
+		{"RSA key as Ed25519", KeyTypeEd25519, rsaPath, "holds an RSA key"},
+		{"Ed25519 key as RSA", KeyTypeRSA, edPath, "holds an Ed25519 key"},
+		{"ECDSA key", KeyTypeRSA, writePEM(t, "PRIVATE KEY", mustPKCS8(t, ecKey)), "unsupported private key type"},
+		{"missing file", KeyTypeRSA, filepath.Join(t.TempDir(), "missing.pem"), "failed to read private key"},
+		{"no PEM data", KeyTypeRSA, notPEM, "no PEM data"},
+	}
+	for _, tt := range tests {
+		_, err := LoadSigner(tt.keyType, tt.secret)
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: err = %v, want it to contain %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+)
+
+// Ticker24h is the rolling 24 hour statistics of a symbol
+type Ticker24h struct {
+	Symbol             string
+	LastPrice          float64
+	PriceChange        float64
+	PriceChangePercent float64
+	HighPrice          float64
+	LowPrice           float64
+	Volume             float64 // base asset volume
+	QuoteVolume        float64
+}
+
+// symbolsParam encodes symbols as the JSON array expected by the symbols= parameter
+func symbolsParam(symbols []string) string {
+	data, _ := json.Marshal(symbols)
+	return string(data)
+}
+
+// GetPrices retrieves the current price of several symbols in one request
+func (b *HttpRequest) GetPrices(symbols []string) (map[string]float64, error) {
+	return b.GetPricesContext(context.Background(), symbols)
+}
+
+// GetPricesContext is GetPrices with a context
+func (b *HttpRequest) GetPricesContext(ctx context.Context, symbols []string) (map[string]float64, error) {
+	prices := make(map[string]float64, len(symbols))
+	if len(symbols) == 0 {
+		return prices, nil
+	}
+
+	body, err := b.PublicRequestContext(ctx, "/api/v3/ticker/price", map[string]string{"symbols": symbolsParam(symbols)})
+	if errors.Is(err, ErrInvalidSymbol) && len(symbols) > 1 {
+		return b.pricesOneByOne(ctx, symbols)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var result []struct {
+		Symbol string `json:"symbol"`
+		Price  string `json:"price"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse prices response: %w", err)
+	}
+
+	for _, r := range result {
+		price, err := strconv.ParseFloat(r.Price, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid price format for %s: %w", r.Symbol, err)
+		}
+		prices[r.Symbol] = price
+	}
+	return prices, nil
+}
+
+// pricesOneByOne prices symbols one request each after one of them (e.g. a
+// delisted or halted market) failed the whole batch. Symbols Binance doesn't
+// know are left out of the result and dropped from the exchangeInfo cache,
+// so they are looked up again instead of being priced every cycle.
+func (b *HttpRequest) pricesOneByOne(ctx context.Context, symbols []string) (map[string]float64, error) {
+	prices := make(map[string]float64, len(symbols))
+	for _, symbol := range symbols {
+		price, err := b.GetPriceContext(ctx, symbol)
+		if errors.Is(err, ErrInvalidSymbol) {
+			log.Printf("[%s] No price: %v\n", symbol, err)
+			b.forgetSymbol(symbol)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		prices[symbol] = price
+	}
+	return prices, nil
+}
+
+// Get24hTickers retrieves the 24hr statistics of several symbols in one request
+func (b *HttpRequest) Get24hTickers(symbols []string) (map[string]Ticker24h, error) {
+	return b.Get24hTickersContext(context.Background(), symbols)
+}
+
+// Get24hTickersContext is Get24hTickers with a context
+func (b *HttpRequest) Get24hTickersContext(ctx context.Context, symbols []string) (map[string]Ticker24h, error) {
+	tickers := make(map[string]Ticker24h, len(symbols))
+	if len(symbols) == 0 {
+		return tickers, nil
+	}
+
+	body, err := b.PublicRequestContext(ctx, "/api/v3/ticker/24hr", map[string]string{"symbols": symbolsParam(symbols)})
+	if err != nil {
+		return nil, err
+	}
+
+	var result []struct {
+		Symbol             string `json:"symbol"`
+		PriceChange        string `json:"priceChange"`
+		PriceChangePercent string `json:"priceChangePercent"`
+		LastPrice          string `json:"lastPrice"`
+		HighPrice          string `json:"highPrice"`
+		LowPrice           string `json:"lowPrice"`
+		Volume             string `json:"volume"`
+		QuoteVolume        string `json:"quoteVolume"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse 24hr ticker response: %w", err)
+	}
+
+	for _, r := range result {
+		t := Ticker24h{Symbol: r.Symbol}
+		t.PriceChange, _ = strconv.ParseFloat(r.PriceChange, 64)
+		t.PriceChangePercent, _ = strconv.ParseFloat(r.PriceChangePercent, 64)
+		t.LastPrice, _ = strconv.ParseFloat(r.LastPrice, 64)
+		t.HighPrice, _ = strconv.ParseFloat(r.HighPrice, 64)
+		t.LowPrice, _ = strconv.ParseFloat(r.LowPrice, 64)
+		t.Volume, _ = strconv.ParseFloat(r.Volume, 64)
+		t.QuoteVolume, _ = strconv.ParseFloat(r.QuoteVolume, 64)
+		tickers[r.Symbol] = t
+	}
+	return tickers, nil
+}
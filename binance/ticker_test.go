@@ -0,0 +1,37 @@
+package binance
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetPricesSkipsInvalidSymbol(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("symbols") != "" || q.Get("symbol") == "GONEUSDT" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"code":-1121,"msg":"Invalid symbol."}`))
+			return
+		}
+		w.Write([]byte(`{"symbol":"` + q.Get("symbol") + `","price":"1.5"}`))
+	}))
+	defer srv.Close()
+
+	b := NewHttpRequest("key", "secret")
+	b.TimeSyncInterval = 0
+	b.SetEndpoints(Endpoints{REST: []string{srv.URL}})
+	b.filters["GONEUSDT"] = &SymbolFilters{Symbol: "GONEUSDT"}
+
+	prices, err := b.GetPricesContext(context.Background(), []string{"BTCUSDT", "GONEUSDT", "ETHUSDT"})
+	if err != nil {
+		t.Fatalf("err = %v, want the other symbols priced", err)
+	}
+	if len(prices) != 2 || prices["BTCUSDT"] != 1.5 || prices["ETHUSDT"] != 1.5 {
+		t.Fatalf("prices = %v, want BTCUSDT and ETHUSDT", prices)
+	}
+	if _, cached := b.filters["GONEUSDT"]; cached {
+		t.Error("filters of the invalid symbol still cached")
+	}
+}
@@ -1,22 +1,41 @@
 package binance
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"sort"
 	"strconv"
 	"time"
 )
 
+// Sources of the entries in an asset's trade ledger
+const (
+	TradeSourceSpot       = ""           // /api/v3/myTrades
+	TradeSourceDeposit    = "DEPOSIT"    // coins transferred in, booked as a buy at their cost basis
+	TradeSourceWithdrawal = "WITHDRAWAL" // coins transferred out, reduce the position without a sale
+	TradeSourceConvert    = "CONVERT"    // Binance Convert, booked at the value of the conversion
+	TradeSourceDust       = "DUST"       // small balances converted to BNB
+)
+
 // Trade represents a single user trade record on Binance
 type Trade struct {
-	Symbol  string
-	Price   float64
-	Qty     float64
-	IsBuyer bool
-	Time    time.Time
+	ID              int64
+	Symbol          string
+	Price           float64
+	Qty             float64
+	IsBuyer         bool
+	Time            time.Time
+	Commission      float64 // fee charged, in CommissionAsset
+	CommissionAsset string  // e.g. BNB, USDT or the base asset
+	CommissionQuote float64 // fee converted to the quote currency at fill time
+	Source          string  // one of the TradeSource constants
 }
 
+// maxTradesPerPage is the largest limit accepted by /api/v3/myTrades
+const maxTradesPerPage = 1000
+
 // Kline represents a simplified kline/candle
 type Kline struct {
 	OpenTime  time.Time
@@ -30,8 +49,21 @@ type Kline struct {
 
 // GetKlines fetches klines (candles) for symbol/interval. interval like "4h". limit optional <=1000
 func (b *HttpRequest) GetKlines(symbol, interval string, limit int) ([]Kline, error) {
+	return b.GetKlinesContext(context.Background(), symbol, interval, limit)
+}
+
+// GetKlinesContext is GetKlines with a context
+func (b *HttpRequest) GetKlinesContext(ctx context.Context, symbol, interval string, limit int) ([]Kline, error) {
+	return b.fetchKlines(ctx, map[string]string{"symbol": symbol, "interval": interval, "limit": strconv.Itoa(limit)})
+}
+
+// ErrNoKlines is returned when Binance has no candle for the requested time
+var ErrNoKlines = errors.New("no klines returned")
+
+// fetchKlines calls /api/v3/klines with params and parses the candles
+func (b *HttpRequest) fetchKlines(ctx context.Context, params map[string]string) ([]Kline, error) {
 	// use PublicRequest to call endpoint but PublicRequest composes endpoint+params, so:
-	body, err := b.PublicRequest("/api/v3/klines", map[string]string{"symbol": symbol, "interval": interval, "limit": strconv.Itoa(limit)})
+	body, err := b.PublicRequestContext(ctx, "/api/v3/klines", params)
 	if err != nil {
 		return nil, fmt.Errorf("GetKlines error: %w", err)
 	}
@@ -75,7 +107,12 @@ func (b *HttpRequest) GetKlines(symbol, interval string, limit int) ([]Kline, er
 
 // GetPrice retrieves the current price for a symbol (e.g., BTCUSDT)
 func (b *HttpRequest) GetPrice(symbol string) (float64, error) {
-	body, err := b.PublicRequest("/api/v3/ticker/price", map[string]string{"symbol": symbol})
+	return b.GetPriceContext(context.Background(), symbol)
+}
+
+// GetPriceContext is GetPrice with a context
+func (b *HttpRequest) GetPriceContext(ctx context.Context, symbol string) (float64, error) {
+	body, err := b.PublicRequestContext(ctx, "/api/v3/ticker/price", map[string]string{"symbol": symbol})
 	if err != nil {
 		return 0, err
 	}
@@ -97,49 +134,97 @@ func (b *HttpRequest) GetPrice(symbol string) (float64, error) {
 	return price, nil
 }
 
-// PlaceOrder places a market buy/sell order
-func (b *HttpRequest) PlaceOrder(symbol, side string, quantity float64) error {
+// GetTradeHistory retrieves the user's trade history for a symbol
+func (b *HttpRequest) GetTradeHistory(symbol string, limit int) ([]Trade, error) {
+	return b.GetTradeHistoryContext(context.Background(), symbol, limit)
+}
+
+// GetTradeHistoryContext is GetTradeHistory with a context
+func (b *HttpRequest) GetTradeHistoryContext(ctx context.Context, symbol string, limit int) ([]Trade, error) {
 	params := map[string]string{
-		"symbol":   symbol,
-		"side":     side,     // BUY or SELL
-		"type":     "MARKET", //LIMIT or MARKET
-		"quantity": fmt.Sprintf("%.6f", quantity),
+		"symbol": symbol,
 	}
-
-	body, err := b.SignedRequest("POST", "/api/v3/order", params)
-	if err != nil {
-		return fmt.Errorf("failed to place order: %w", err)
+	if limit > 0 {
+		params["limit"] = fmt.Sprintf("%d", limit)
 	}
 
-	var result struct {
-		OrderId int64  `json:"orderId"`
-		Status  string `json:"status"`
+	return b.fetchTrades(ctx, symbol, params)
+}
+
+// GetTradeHistoryFromContext pages through /api/v3/myTrades starting at trade
+// fromID (0 for the very first trade) until the end of the history
+func (b *HttpRequest) GetTradeHistoryFromContext(ctx context.Context, symbol string, fromID int64) ([]Trade, error) {
+	var trades []Trade
+	for {
+		page, err := b.fetchTrades(ctx, symbol, map[string]string{
+			"symbol": symbol,
+			"fromId": strconv.FormatInt(fromID, 10),
+			"limit":  strconv.Itoa(maxTradesPerPage),
+		})
+		if err != nil {
+			return nil, err
+		}
+		trades = append(trades, page...)
+		if len(page) < maxTradesPerPage {
+			return trades, nil
+		}
+		for _, t := range page {
+			if t.ID >= fromID {
+				fromID = t.ID + 1
+			}
+		}
 	}
-	_ = json.Unmarshal(body, &result)
-	fmt.Printf("✅ Order placed: %s %s (ID: %d, Status: %s)\n", side, symbol, result.OrderId, result.Status)
-	return nil
 }
 
-// GetTradeHistory retrieves the user's trade history for a symbol
-func (b *HttpRequest) GetTradeHistory(symbol string, limit int) ([]Trade, error) {
-	params := map[string]string{
-		"symbol": symbol,
+// GetAllTrades returns the complete trade history for a symbol
+func (b *HttpRequest) GetAllTrades(symbol string) ([]Trade, error) {
+	return b.GetAllTradesContext(context.Background(), symbol)
+}
+
+// GetAllTradesContext returns the complete trade history for a symbol. The
+// history is cached, so later calls only fetch trades newer than the last seen ID.
+func (b *HttpRequest) GetAllTradesContext(ctx context.Context, symbol string) ([]Trade, error) {
+	b.tradesMu.Lock()
+	cached := b.trades[symbol]
+	b.tradesMu.Unlock()
+
+	var fromID int64
+	if len(cached) > 0 {
+		fromID = cached[len(cached)-1].ID + 1
 	}
-	if limit > 0 {
-		params["limit"] = fmt.Sprintf("%d", limit)
+
+	newer, err := b.GetTradeHistoryFromContext(ctx, symbol, fromID)
+	if err != nil {
+		return nil, err
 	}
 
-	body, err := b.SignedRequest("GET", "/api/v3/myTrades", params)
+	trades := make([]Trade, 0, len(cached)+len(newer))
+	trades = append(trades, cached...)
+	trades = append(trades, newer...)
+
+	b.tradesMu.Lock()
+	b.trades[symbol] = trades
+	b.tradesMu.Unlock()
+
+	return append([]Trade(nil), trades...), nil
+}
+
+// fetchTrades calls /api/v3/myTrades and parses one page of trades
+func (b *HttpRequest) fetchTrades(ctx context.Context, symbol string, params map[string]string) ([]Trade, error) {
+	body, err := b.SignedRequestContext(ctx, "GET", "/api/v3/myTrades", params)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch trade history: %w", err)
 	}
 
 	// Struct matching Binance API JSON response
 	var rawTrades []struct {
-		Price   string `json:"price"`
-		Qty     string `json:"qty"`
-		IsBuyer bool   `json:"isBuyer"`
-		Time    int64  `json:"time"`
+		ID              int64  `json:"id"`
+		Price           string `json:"price"`
+		Qty             string `json:"qty"`
+		Commission      string `json:"commission"`
+		CommissionAsset string `json:"commissionAsset"`
+		IsBuyer         bool   `json:"isBuyer"`
+		Time            int64  `json:"time"`
 	}
 	if err := json.Unmarshal(body, &rawTrades); err != nil {
 		return nil, fmt.Errorf("failed to parse trade history: %w", err)
@@ -149,18 +234,24 @@ func (b *HttpRequest) GetTradeHistory(symbol string, limit int) ([]Trade, error)
 	for _, t := range rawTrades {
 		price, _ := strconv.ParseFloat(t.Price, 64)
 		qty, _ := strconv.ParseFloat(t.Qty, 64)
+		commission, _ := strconv.ParseFloat(t.Commission, 64)
 		trades = append(trades, Trade{
-			Symbol:  symbol,
-			Price:   price,
-			Qty:     qty,
-			IsBuyer: t.IsBuyer,
-			Time:    time.UnixMilli(t.Time),
+			ID:              t.ID,
+			Symbol:          symbol,
+			Price:           price,
+			Qty:             qty,
+			IsBuyer:         t.IsBuyer,
+			Time:            time.UnixMilli(t.Time),
+			Commission:      commission,
+			CommissionAsset: t.CommissionAsset,
 		})
 	}
 	// ✅ Ensure chronological order (FIFO)
 	sort.Slice(trades, func(i, j int) bool {
+		if trades[i].Time.Equal(trades[j].Time) {
+			return trades[i].ID < trades[j].ID
+		}
 		return trades[i].Time.Before(trades[j].Time)
 	})
 	return trades, nil
-
 }
@@ -0,0 +1,309 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+)
+
+// listenKeyKeepAlive is how often the listenKey is extended; it expires after 60 minutes
+const listenKeyKeepAlive = 30 * time.Minute
+
+// ExecutionReport is an order update from the user data stream
+type ExecutionReport struct {
+	Symbol           string
+	ClientOrderID    string
+	OrderID          int64
+	Side             string // BUY or SELL
+	Type             string // MARKET, LIMIT, ...
+	ExecutionType    string // NEW, TRADE, CANCELED, REJECTED, EXPIRED
+	Status           string // NEW, PARTIALLY_FILLED, FILLED, ...
+	Quantity         float64
+	LastQty          float64 // quantity of this fill
+	LastPrice        float64 // price of this fill
+	CumulativeQty    float64
+	CumulativeQuote  float64
+	Commission       float64
+	CommissionAsset  string
+	TradeID          int64
+	TransactionTime  time.Time
+	RejectReason     string
+	AverageFillPrice float64 // CumulativeQuote / CumulativeQty
+	IsFinal          bool    // no further updates will follow for this order
+}
+
+// AssetPosition is the free and locked amount of one asset
+type AssetPosition struct {
+	Asset     string
+	Free      float64
+	Locked    float64
+	UpdatedAt time.Time
+}
+
+// UserStream listens to the listenKey based user data stream and hands order
+// updates and account positions to its callbacks.
+type UserStream struct {
+	URLs []string // websocket base URLs; a failed connect moves on to the next
+
+	OnExecutionReport func(ExecutionReport)
+	OnAccountPosition func([]AssetPosition)
+
+	rest *HttpRequest
+	url  int // index into URLs of the host in use
+}
+
+// NewUserStream creates a UserStream that manages its listenKey through rest
+func NewUserStream(rest *HttpRequest) *UserStream {
+	return &UserStream{
+		URLs: rest.Endpoints().Stream,
+		rest: rest,
+	}
+}
+
+// CreateListenKey starts a new user data stream
+func (b *HttpRequest) CreateListenKey(ctx context.Context) (string, error) {
+	body, err := b.APIKeyRequestContext(ctx, "POST", "/api/v3/userDataStream", nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create listenKey: %w", err)
+	}
+	var result struct {
+		ListenKey string `json:"listenKey"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("failed to parse listenKey: %w", err)
+	}
+	return result.ListenKey, nil
+}
+
+// KeepAliveListenKey extends the validity of a listenKey by 60 minutes
+func (b *HttpRequest) KeepAliveListenKey(ctx context.Context, listenKey string) error {
+	_, err := b.APIKeyRequestContext(ctx, "PUT", "/api/v3/userDataStream", map[string]string{"listenKey": listenKey})
+	if err != nil {
+		return fmt.Errorf("failed to keep listenKey alive: %w", err)
+	}
+	return nil
+}
+
+// CloseListenKey closes a user data stream
+func (b *HttpRequest) CloseListenKey(ctx context.Context, listenKey string) error {
+	_, err := b.APIKeyRequestContext(ctx, "DELETE", "/api/v3/userDataStream", map[string]string{"listenKey": listenKey})
+	if err != nil {
+		return fmt.Errorf("failed to close listenKey: %w", err)
+	}
+	return nil
+}
+
+// errListenKeyExpired ends a connection whose listenKey is no longer valid
+var errListenKeyExpired = errors.New("listenKey expired")
+
+// Run keeps the user data stream connected until ctx is cancelled
+func (s *UserStream) Run(ctx context.Context) error {
+	backoff := time.Second
+	for {
+		start := time.Now()
+		err := s.runOnce(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err == nil {
+			backoff = time.Second
+			continue
+		}
+
+		if time.Since(start) > streamMaxBackoff {
+			backoff = time.Second // the connection was healthy for a while
+		}
+		log.Printf("⚠️  User stream disconnected: %v. Reconnecting in %s\n", err, backoff)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, streamMaxBackoff)
+	}
+}
+
+// runOnce serves a single listenKey connection
+func (s *UserStream) runOnce(ctx context.Context) error {
+	listenKey, err := s.rest.CreateListenKey(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// the parent context may be gone already
+		closeCtx, cancel := context.WithTimeout(context.Background(), streamWriteTimeout)
+		defer cancel()
+		s.rest.CloseListenKey(closeCtx, listenKey)
+	}()
+
+	conn, err := dialStream(ctx, s.URLs[s.url%len(s.URLs)]+"/ws/"+listenKey)
+	if err != nil {
+		s.url++
+		return fmt.Errorf("failed to connect user stream: %w", err)
+	}
+	defer conn.Close()
+	log.Println("📡 User stream connected")
+
+	expired := make(chan struct{}, 1)
+	readErr := readStream(conn, func(data []byte) {
+		if s.handleMessage(data) == errListenKeyExpired {
+			select {
+			case expired <- struct{}{}:
+			default:
+			}
+		}
+	})
+
+	keepAlive := time.NewTicker(listenKeyKeepAlive)
+	defer keepAlive.Stop()
+	rollover := time.NewTimer(streamMaxLifetime)
+	defer rollover.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			closeStream(conn)
+			return nil
+		case <-keepAlive.C:
+			if err := s.rest.KeepAliveListenKey(ctx, listenKey); err != nil {
+				return err
+			}
+		case <-expired:
+			return errListenKeyExpired
+		case <-rollover.C:
+			log.Println("🔄 User stream 24h rollover")
+			return nil
+		case err := <-readErr:
+			return err
+		}
+	}
+}
+
+func (s *UserStream) handleMessage(data []byte) error {
+	var head struct {
+		Event     string `json:"e"`
+		EventTime int64  `json:"E"` // keeps "E" from matching Event case-insensitively
+	}
+	if err := json.Unmarshal(data, &head); err != nil {
+		log.Printf("⚠️  User stream: bad message: %v\n", err)
+		return nil
+	}
+
+	switch head.Event {
+	case "executionReport":
+		report, err := parseExecutionReport(data)
+		if err != nil {
+			log.Printf("⚠️  User stream: %v\n", err)
+			return nil
+		}
+		if s.OnExecutionReport != nil {
+			s.OnExecutionReport(report)
+		}
+	case "outboundAccountPosition":
+		positions, err := parseAccountPosition(data)
+		if err != nil {
+			log.Printf("⚠️  User stream: %v\n", err)
+			return nil
+		}
+		if s.OnAccountPosition != nil {
+			s.OnAccountPosition(positions)
+		}
+	case "listenKeyExpired":
+		return errListenKeyExpired
+	}
+	return nil
+}
+
+func parseExecutionReport(data []byte) (ExecutionReport, error) {
+	// encoding/json matches keys case-insensitively, so the unused
+	// counterparts of used keys (C, O, Q, I) are declared too
+	var raw struct {
+		Symbol          string `json:"s"`
+		ClientOrderID   string `json:"c"`
+		OrigClientID    string `json:"C"`
+		OrderCreation   int64  `json:"O"`
+		QuoteOrderQty   string `json:"Q"`
+		Ignore          int64  `json:"I"`
+		Side            string `json:"S"`
+		Type            string `json:"o"`
+		Quantity        string `json:"q"`
+		ExecutionType   string `json:"x"`
+		Status          string `json:"X"`
+		RejectReason    string `json:"r"`
+		OrderID         int64  `json:"i"`
+		LastQty         string `json:"l"`
+		CumulativeQty   string `json:"z"`
+		LastPrice       string `json:"L"`
+		Commission      string `json:"n"`
+		CommissionAsset string `json:"N"`
+		TransactionTime int64  `json:"T"`
+		TradeID         int64  `json:"t"`
+		CumulativeQuote string `json:"Z"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return ExecutionReport{}, fmt.Errorf("failed to parse executionReport: %w", err)
+	}
+
+	r := ExecutionReport{
+		Symbol:          raw.Symbol,
+		ClientOrderID:   raw.ClientOrderID,
+		OrderID:         raw.OrderID,
+		Side:            raw.Side,
+		Type:            raw.Type,
+		ExecutionType:   raw.ExecutionType,
+		Status:          raw.Status,
+		CommissionAsset: raw.CommissionAsset,
+		TradeID:         raw.TradeID,
+		TransactionTime: time.UnixMilli(raw.TransactionTime),
+		RejectReason:    raw.RejectReason,
+	}
+	r.Quantity, _ = strconv.ParseFloat(raw.Quantity, 64)
+	r.LastQty, _ = strconv.ParseFloat(raw.LastQty, 64)
+	r.LastPrice, _ = strconv.ParseFloat(raw.LastPrice, 64)
+	r.CumulativeQty, _ = strconv.ParseFloat(raw.CumulativeQty, 64)
+	r.CumulativeQuote, _ = strconv.ParseFloat(raw.CumulativeQuote, 64)
+	r.Commission, _ = strconv.ParseFloat(raw.Commission, 64)
+	if r.CumulativeQty > 0 {
+		r.AverageFillPrice = r.CumulativeQuote / r.CumulativeQty
+	}
+	switch r.Status {
+	case "FILLED", "CANCELED", "REJECTED", "EXPIRED", "EXPIRED_IN_MATCH":
+		r.IsFinal = true
+	}
+	return r, nil
+}
+
+// parseAccountPosition reads the balances of an outboundAccountPosition event
+func parseAccountPosition(data []byte) ([]AssetPosition, error) {
+	// "e" needs its own field or the event type is matched to "E"
+	var raw struct {
+		Event     string `json:"e"`
+		EventTime int64  `json:"E"`
+		UpdatedAt int64  `json:"u"`
+		Balances  []struct {
+			Asset  string `json:"a"`
+			Free   string `json:"f"`
+			Locked string `json:"l"`
+		} `json:"B"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse outboundAccountPosition: %w", err)
+	}
+
+	positions := make([]AssetPosition, 0, len(raw.Balances))
+	for _, bal := range raw.Balances {
+		free, _ := strconv.ParseFloat(bal.Free, 64)
+		locked, _ := strconv.ParseFloat(bal.Locked, 64)
+		positions = append(positions, AssetPosition{
+			Asset:     bal.Asset,
+			Free:      free,
+			Locked:    locked,
+			UpdatedAt: time.UnixMilli(raw.UpdatedAt),
+		})
+	}
+	return positions, nil
+}
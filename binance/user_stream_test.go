@@ -0,0 +1,49 @@
+package binance
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseAccountPosition(t *testing.T) {
+	// payload from the Binance user data stream docs
+	data := []byte(`{
+		"e": "outboundAccountPosition",
+		"E": 1564034571105,
+		"u": 1564034571073,
+		"B": [
+			{"a": "ETH", "f": "10000.000000", "l": "0.000000"},
+			{"a": "BTC", "f": "0.50000000", "l": "0.25000000"}
+		]
+	}`)
+
+	positions, err := parseAccountPosition(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []AssetPosition{
+		{Asset: "ETH", Free: 10000, Locked: 0, UpdatedAt: time.UnixMilli(1564034571073)},
+		{Asset: "BTC", Free: 0.5, Locked: 0.25, UpdatedAt: time.UnixMilli(1564034571073)},
+	}
+	if len(positions) != len(want) {
+		t.Fatalf("got %d positions, want %d", len(positions), len(want))
+	}
+	for i := range want {
+		if positions[i] != want[i] {
+			t.Errorf("position %d = %+v, want %+v", i, positions[i], want[i])
+		}
+	}
+}
+
+func TestHandleMessageAccountPosition(t *testing.T) {
+	var got []AssetPosition
+	s := &UserStream{OnAccountPosition: func(p []AssetPosition) { got = p }}
+
+	err := s.handleMessage([]byte(`{"e":"outboundAccountPosition","E":1564034571105,"u":1564034571073,"B":[{"a":"ETH","f":"1.5","l":"0.5"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Asset != "ETH" || got[0].Free != 1.5 || got[0].Locked != 0.5 {
+		t.Fatalf("OnAccountPosition got %+v", got)
+	}
+}
@@ -0,0 +1,239 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Ways to value coins that were deposited rather than bought
+const (
+	CostBasisMarket = "market" // price at deposit time from 1m klines
+	CostBasisManual = "manual" // only ManualCostBasis; other deposits are ignored
+)
+
+const (
+	transferWindow          = 90 * 24 * time.Hour // longest range the wallet history endpoints accept
+	transferPageSize        = 1000
+	transferRefreshInterval = 5 * time.Minute
+	transferPendingWindow   = 7 * 24 * time.Hour // re-read so pending transfers are picked up once complete
+	defaultTransferLookback = 3 * 365 * 24 * time.Hour
+	withdrawTimeLayout      = "2006-01-02 15:04:05" // UTC
+)
+
+// Transfer is a completed deposit into or withdrawal from the spot wallet
+type Transfer struct {
+	ID      string
+	Asset   string
+	Amount  float64 // credited or withdrawn amount
+	Fee     float64 // withdrawal fee, in Asset
+	Time    time.Time
+	Deposit bool
+	TxID    string
+}
+
+// GetTransfers returns the completed deposits and withdrawals since
+// TransferHistoryStart. The history is cached and refreshed incrementally.
+func (b *HttpRequest) GetTransfers() ([]Transfer, error) {
+	return b.GetTransfersContext(context.Background())
+}
+
+// GetTransfersContext is GetTransfers with a context
+func (b *HttpRequest) GetTransfersContext(ctx context.Context) ([]Transfer, error) {
+	b.transfersMu.Lock()
+	defer b.transfersMu.Unlock()
+
+	now := time.Now()
+	if time.Since(b.transfersAt) < transferRefreshInterval {
+		return b.transferList(), nil
+	}
+
+	from := b.TransferHistoryStart
+	if !b.transfersAt.IsZero() {
+		from = b.transfersAt.Add(-transferPendingWindow)
+	}
+	for start := from; start.Before(now); start = start.Add(transferWindow) {
+		end := start.Add(transferWindow)
+		if end.After(now) {
+			end = now
+		}
+		deposits, err := b.fetchDeposits(ctx, start, end)
+		if err != nil {
+			return nil, err
+		}
+		withdrawals, err := b.fetchWithdrawals(ctx, start, end)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range append(deposits, withdrawals...) {
+			key := "W" + t.ID
+			if t.Deposit {
+				key = "D" + t.ID
+			}
+			b.transfers[key] = t
+		}
+	}
+	b.transfersAt = now
+	return b.transferList(), nil
+}
+
+func (b *HttpRequest) transferList() []Transfer {
+	list := make([]Transfer, 0, len(b.transfers))
+	for _, t := range b.transfers {
+		list = append(list, t)
+	}
+	return list
+}
+
+// fetchDeposits pages through /sapi/v1/capital/deposit/hisrec for [start, end)
+func (b *HttpRequest) fetchDeposits(ctx context.Context, start, end time.Time) ([]Transfer, error) {
+	var transfers []Transfer
+	for offset := 0; ; offset += transferPageSize {
+		body, err := b.SignedRequestContext(ctx, "GET", "/sapi/v1/capital/deposit/hisrec", map[string]string{
+			"startTime": strconv.FormatInt(start.UnixMilli(), 10),
+			"endTime":   strconv.FormatInt(end.UnixMilli()-1, 10),
+			"offset":    strconv.Itoa(offset),
+			"limit":     strconv.Itoa(transferPageSize),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch deposit history: %w", err)
+		}
+
+		var raw []struct {
+			ID         string `json:"id"`
+			Amount     string `json:"amount"`
+			Coin       string `json:"coin"`
+			Status     int    `json:"status"`
+			TxID       string `json:"txId"`
+			InsertTime int64  `json:"insertTime"`
+		}
+		if err := json.Unmarshal(body, &raw); err != nil {
+			return nil, fmt.Errorf("failed to parse deposit history: %w", err)
+		}
+		for _, d := range raw {
+			// 1 success, 6 credited but cannot withdraw yet
+			if d.Status != 1 && d.Status != 6 {
+				continue
+			}
+			amount, _ := strconv.ParseFloat(d.Amount, 64)
+			transfers = append(transfers, Transfer{
+				ID:      d.ID,
+				Asset:   d.Coin,
+				Amount:  amount,
+				Time:    time.UnixMilli(d.InsertTime),
+				Deposit: true,
+				TxID:    d.TxID,
+			})
+		}
+		if len(raw) < transferPageSize {
+			return transfers, nil
+		}
+	}
+}
+
+// fetchWithdrawals pages through /sapi/v1/capital/withdraw/history for [start, end)
+func (b *HttpRequest) fetchWithdrawals(ctx context.Context, start, end time.Time) ([]Transfer, error) {
+	var transfers []Transfer
+	for offset := 0; ; offset += transferPageSize {
+		body, err := b.SignedRequestContext(ctx, "GET", "/sapi/v1/capital/withdraw/history", map[string]string{
+			"startTime": strconv.FormatInt(start.UnixMilli(), 10),
+			"endTime":   strconv.FormatInt(end.UnixMilli()-1, 10),
+			"offset":    strconv.Itoa(offset),
+			"limit":     strconv.Itoa(transferPageSize),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch withdrawal history: %w", err)
+		}
+
+		var raw []struct {
+			ID             string `json:"id"`
+			Amount         string `json:"amount"`
+			TransactionFee string `json:"transactionFee"`
+			Coin           string `json:"coin"`
+			Status         int    `json:"status"`
+			TxID           string `json:"txId"`
+			ApplyTime      string `json:"applyTime"`
+		}
+		if err := json.Unmarshal(body, &raw); err != nil {
+			return nil, fmt.Errorf("failed to parse withdrawal history: %w", err)
+		}
+		for _, w := range raw {
+			// 6 completed
+			if w.Status != 6 {
+				continue
+			}
+			amount, _ := strconv.ParseFloat(w.Amount, 64)
+			fee, _ := strconv.ParseFloat(w.TransactionFee, 64)
+			applied, _ := time.Parse(withdrawTimeLayout, w.ApplyTime)
+			transfers = append(transfers, Transfer{
+				ID:     w.ID,
+				Asset:  w.Coin,
+				Amount: amount,
+				Fee:    fee,
+				Time:   applied,
+				TxID:   w.TxID,
+			})
+		}
+		if len(raw) < transferPageSize {
+			return transfers, nil
+		}
+	}
+}
+
+// transferTrades turns the deposits and withdrawals of asset into synthetic
+// trades: deposits are buys at their cost basis, withdrawals reduce the
+// position like a sell of the amount plus the fee
+func (b *HttpRequest) transferTrades(ctx context.Context, asset string) ([]Trade, error) {
+	transfers, err := b.GetTransfersContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var trades []Trade
+	for _, t := range transfers {
+		if t.Asset != asset {
+			continue
+		}
+		if !t.Deposit {
+			// the price only matters for reporting; withdrawals don't realize PnL
+			price, _ := b.conversionRate(ctx, asset, t.Time)
+			trades = append(trades, Trade{
+				Symbol: asset + b.ReportingAsset,
+				Price:  price,
+				Qty:    t.Amount + t.Fee,
+				Time:   t.Time,
+				Source: TradeSourceWithdrawal,
+			})
+			continue
+		}
+
+		price, err := b.depositCostBasis(ctx, asset, t.Time)
+		if err != nil {
+			fmt.Printf("⚠️  %s: deposit %s ignored for cost basis: %v\n", asset, t.ID, err)
+			continue
+		}
+		trades = append(trades, Trade{
+			Symbol:  asset + b.ReportingAsset,
+			Price:   price,
+			Qty:     t.Amount,
+			IsBuyer: true,
+			Time:    t.Time,
+			Source:  TradeSourceDeposit,
+		})
+	}
+	return trades, nil
+}
+
+// depositCostBasis returns the price per unit, in the reporting currency,
+// that a deposit of asset at t is booked at
+func (b *HttpRequest) depositCostBasis(ctx context.Context, asset string, t time.Time) (float64, error) {
+	if price, ok := b.ManualCostBasis[asset]; ok {
+		return price, nil
+	}
+	if b.DepositCostBasis == CostBasisManual {
+		return 0, fmt.Errorf("no manual cost basis for %s", asset)
+	}
+	return b.conversionRate(ctx, asset, t)
+}
@@ -4,6 +4,7 @@ go 1.25.0
 
 require (
 	github.com/go-telegram/bot v1.17.0
+	github.com/gorilla/websocket v1.5.3
 	github.com/joho/godotenv v1.5.1
 	github.com/robfig/cron/v3 v3.0.1
-)
\ No newline at end of file
+)
@@ -1,12 +1,13 @@
 package main
 
 import (
-	"errors"
 	"flag"
 	"fmt"
 	"log"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"context"
 	"os/signal"
@@ -18,210 +19,20 @@ import (
 
 	"main.go/binance"
 	"main.go/notifier"
-	"main.go/utils"
+	"main.go/trader"
 )
 
 var (
-	apiKey               string
-	secretKey            string
-	tgToken              string
-	tgChatID             string
-	interval             string  = "4h" // default interval for klines
-	percentThreshold     float64 = 10.0 // percentage change threshold for alerts
-	percentThresholdBuy  float64 = 10.0 // percentage change threshold buy for alerts
-	percentThresholdSell float64 = 15.0 // percentage change threshold sell for alerts
-	minQuantity          float64 = 5.0  // minimum quantity to trade
-
-	api      *binance.HttpRequest
-	telegram *notifier.TelegramNotifier
-)
-
-// =================== Worker ======================
-func checkSignal(symbol string, change float64) (*utils.PredictResult, error) {
-	klines, err := api.GetKlines(symbol, interval, 200)
-	if err != nil {
-		log.Printf("GetKlines failed: %w", err)
-		return nil, err
-	}
-	if len(klines) < 29 {
-		log.Printf("not enough klines for RSI: have=%d", len(klines))
-		return nil, errors.New("not enough klines for RSI")
-	}
-
-	// collect closes in chronological order
-	closes := make([]float64, len(klines))
-	for i := range klines {
-		closes[i] = klines[i].Close
-	}
-
-	prediction, err := utils.PredictNextPrice(closes)
-	if err != nil {
-		fmt.Println("❌ Error:", err)
-		return nil, err
-	}
-	// Fetch daily high (1D interval)
-	dayKlines, err := api.GetKlines(symbol, "1d", 1)
-	if err != nil {
-		log.Printf("GetKlines 1d failed: %v", err)
-	} else if len(dayKlines) > 0 {
-		prediction.DayHigh = dayKlines[0].High
-		prediction.DayLow = dayKlines[0].Low
-	}
-
-	return prediction, nil
-}
-
-func autoTrade(balance binance.AccountBalance) string {
-	msg := ""
-	price, err := api.GetPrice(balance.Symbol)
-	if err != nil {
-		log.Println("Price error:", err)
-		return msg
-	}
-
-	currentValueUSDT := price * balance.Free
-	pnlUSDT := currentValueUSDT - balance.TotalUSDT
-	profitOrLoss := fmt.Sprintf("Loss: %.2f USDT", pnlUSDT)
-	if pnlUSDT > 0 {
-		profitOrLoss = fmt.Sprintf("Profit: %.2f USDT", pnlUSDT)
-	}
-	change := (price - balance.AveragePrice) / balance.AveragePrice * 100
-
-	fmt.Printf("[%s] Qty: %.8f | Entry Price: %.8f | Average Price: %.8f | Current: %.8f | Total: %.8f | PnL: %.8f (%.2f%%)\n",
-		balance.Symbol,
-		balance.Free,
-		balance.CostPrice,
-		balance.AveragePrice,
-		price,
-		balance.TotalUSDT,
-		pnlUSDT,
-		change)
-
-	if change > -percentThreshold && change < percentThreshold {
-		return msg // no significant change, skip
-	}
-
-	prediction, err := checkSignal(balance.Symbol, change)
-	if err != nil {
-		fmt.Println("❌ Error:", err)
-		return msg
-	}
-
-	msg += fmt.Sprintf("🚀🚀🚀 *Auto-Trade for: #%s * \nPnL: %.2f%% (%.8f → %.8f)\n%s\nSignal: *%s* \nQuantity: %.8f  \nEntry Price: %.8f \nAverage Price: %.8f \nCurrent Price: %.8f \nHigh:  %.8f - Low: %.8f  \nNext Price: %.8f (%+.2f%%)",
-		balance.Symbol,
-		change,
-		balance.AveragePrice,
-		price,
-		profitOrLoss,
-		prediction.Signal,
-		balance.Free,
-		balance.CostPrice,
-		balance.AveragePrice,
-		price,
-		prediction.DayHigh,
-		prediction.DayLow,
-		prediction.NextPrice,
-		prediction.ChangePct)
-	if change <= -percentThreshold {
-		results, _ := utils.CalculateDCA(balance.Symbol, price, balance.Free, balance.AveragePrice)
-		fmt.Printf("📊 DCA Strategy for %s\n", balance.Symbol)
-
-		msg += fmt.Sprintf("\n\n📊 DCA Strategy for #%s\n", balance.Symbol)
-		for _, r := range results {
-			fmt.Printf("🎯 Target Avg: %.2f USDT |  Drop: %.2f%% | Buy: %.1f | Total: %.1f | Cost: %.2f USDT\n",
-				r.TargetAvg, r.DropPct, r.BuyQty, r.NewTotal, r.USDTSpent)
-
-			msg += fmt.Sprintf("🎯 Target Avg: %.2f USDT | Buy: %.1f | Total: %.1f | Cost: %.2f USDT\n", r.TargetAvg, r.BuyQty, r.NewTotal, r.USDTSpent)
-		}
-	}
-
-	if (change > percentThresholdSell && balance.Free >= minQuantity) &&
-		(price >= prediction.DayHigh || prediction.Signal == "SELL") {
-		if err := api.PlaceOrder(balance.Symbol, "SELL", minQuantity); err != nil {
-			log.Printf("Sell order error #%s: %v\n", balance.Symbol, err)
-			return msg
-		}
-
-		msg += fmt.Sprintf("\n\nPartial Take-Profit: Sold %.1f units.", minQuantity)
-	}
-
-	if prediction.Signal == "BUY" && change <= -percentThresholdBuy {
-		if err := api.PlaceOrder(balance.Symbol, "BUY", minQuantity); err != nil {
-			log.Printf("Buy order error %s: %v\n", balance.Symbol, err)
-			return msg
-		}
-		msg += fmt.Sprintf("\n\nDCA Buy Order: Bought %.1f units.", minQuantity)
-	}
+	apiKey    string
+	secretKey string
+	keyType   string
+	tgToken   string
+	tgChatID  string
 
-	return msg
-}
-
-func cronJob() {
-	balances, err := api.GetAccountBalances()
-	if err != nil {
-		log.Println("Error getting balances:", err)
-		return
-	}
-
-	log.Println("📊 Checking Account Balances:")
-
-	for _, balance := range balances {
-		// if we couldn't compute buy price from trade history, skip
-		if balance.AveragePrice <= 0 {
-			log.Printf("[%s] No AveragePrice from account history (Qty: %.8f). Skipping.\n", balance.Asset, balance.Total)
-			continue
-		}
-		msg := autoTrade(balance)
-		if msg != "" {
-			if err := telegram.Send(msg); err != nil {
-				log.Printf("Telegram send error: %v\n", err)
-			}
-			log.Printf("Telegram message sent for %s\n", balance.Symbol)
-		}
-	}
-}
-
-func summarizeBalances() {
-	balances, err := api.GetAccountBalances()
-	if err != nil {
-		log.Println("Error getting balances:", err)
-		return
-	}
-
-	log.Println("📊 Account Balances Summary:")
-	msg := "📊 *Account Balances Summary:*\n\n"
-	totalUSDT := 0.0
-	totalCurrentUSDT := 0.0
-	totalProfitLoss := 0.0
-	for _, balance := range balances {
-		price, err := api.GetPrice(balance.Symbol)
-		if err != nil {
-			log.Println("Price error:", err)
+	cfg = trader.DefaultConfig()
 
-		}
-
-		if balance.TotalUSDT > 0 {
-			currentValueUSDT := price * balance.Total
-			pnlUSDT := currentValueUSDT - balance.TotalUSDT
-			change := (price - balance.AveragePrice) / balance.AveragePrice * 100
-			totalUSDT += balance.TotalUSDT
-			totalCurrentUSDT += currentValueUSDT
-			totalProfitLoss += pnlUSDT
-			fmt.Printf("[%s]: Qty: %.8f | Avg Price: %.8f | Current Price: %.8f | Total: %.2f USDT. | %.2f PNL: %.2f (%.2f%%)\n",
-				balance.Symbol, balance.Total, balance.AveragePrice, price, balance.TotalUSDT, currentValueUSDT, pnlUSDT, change)
-			msg += fmt.Sprintf("[#%s]: %.4f - Avg: %.4f - PnL: %.2f (%.2f%%)\n",
-				balance.Symbol, balance.Free, balance.AveragePrice, pnlUSDT, change)
-		}
-	}
-	totalChange := (totalCurrentUSDT - totalUSDT) / totalUSDT * 100
-	fmt.Printf("Total Portfolio Value: %.2f USDT. Current: %.2f. PnL: %.2f (%.2f%%)\n", totalUSDT, totalCurrentUSDT, totalProfitLoss, totalChange)
-	msg += fmt.Sprintf("\n*Total Portfolio Value:* %.2f USDT. \n*Current:* %.2f USDT. \n*PNL:* %.2f USDT (%.2f%%)", totalUSDT, totalCurrentUSDT, totalProfitLoss, totalChange)
-	if err := telegram.Send(msg); err != nil {
-		log.Printf("Telegram send error: %v\n", err)
-	} else {
-		log.Println("Telegram summary message sent.")
-	}
-}
+	autoTrader *trader.Trader
+)
 
 func handler(ctx context.Context, b *bot.Bot, update *models.Update) {
 	if update.Message == nil { // ignore non-Message updates
@@ -241,6 +52,7 @@ func handler(ctx context.Context, b *bot.Bot, update *models.Update) {
 			"/start - Start the bot\n" +
 			"/help - Show this help message\n" +
 			"/balance - Show account balance summary\n" +
+			"/lots - Show open lots and realized PnL per sale\n" +
 			"/run - Run the trading job immediately\n" +
 			"/schedule - Schedule the trading job every 5 minutes\n" +
 			"/stop - Stop the scheduled trading job\n" +
@@ -252,11 +64,15 @@ func handler(ctx context.Context, b *bot.Bot, update *models.Update) {
 		return
 	}
 	if update.Message.Text == "/balance" {
-		summarizeBalances()
+		autoTrader.SummarizeBalances(ctx)
+		return
+	}
+	if update.Message.Text == "/lots" {
+		autoTrader.ReportLots(ctx)
 		return
 	}
 	if update.Message.Text == "/run" {
-		cronJob()
+		autoTrader.CronJob(ctx)
 		return
 	}
 	// Echo the received message back to the user
@@ -267,6 +83,17 @@ func handler(ctx context.Context, b *bot.Bot, update *models.Update) {
 	})
 }
 
+// splitList splits a comma separated environment value, dropping empty items
+func splitList(v string) []string {
+	var items []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 // =================== MAIN ======================
 func main() {
 	err := godotenv.Load()
@@ -278,51 +105,198 @@ func main() {
 	secretKey = os.Getenv("BINANCE_SECRET_KEY")
 	tgToken = os.Getenv("TELEGRAM_TOKEN")
 	tgChatID = os.Getenv("TELEGRAM_CHAT_ID")
-	interval = os.Getenv("INTERVAL")
+	if v := os.Getenv("INTERVAL"); v != "" {
+		cfg.Interval = v
+	}
 
-	if apiKey == "" || secretKey == "" || tgToken == "" || tgChatID == "" {
+	if apiKey == "" || tgToken == "" || tgChatID == "" {
 		log.Fatal("Missing API keys or Telegram config in .env")
 	}
 
+	// HMAC signs with BINANCE_SECRET_KEY; RSA and ED25519 with the PEM private key at BINANCE_PRIVATE_KEY_PATH
+	keyType = strings.ToUpper(os.Getenv("BINANCE_KEY_TYPE"))
+	signerSecret := secretKey
+	if keyType != "" && keyType != binance.KeyTypeHMAC {
+		signerSecret = os.Getenv("BINANCE_PRIVATE_KEY_PATH")
+	}
+	signer, err := binance.LoadSigner(keyType, signerSecret)
+	if err != nil {
+		log.Fatal("Invalid Binance signing key: ", err)
+	}
+
 	var percentThresholdString = os.Getenv("PERCENT_THRESHOLD")
 
 	if percentThresholdString != "" {
 		if v, err := strconv.ParseFloat(percentThresholdString, 64); err == nil {
-			percentThreshold = v
+			cfg.PercentThreshold = v
 		} else {
-			log.Printf("Warning: invalid PERCENT_THRESHOLD: %v. Using default %.2f\n", err, percentThreshold)
+			log.Printf("Warning: invalid PERCENT_THRESHOLD: %v. Using default %.2f\n", err, cfg.PercentThreshold)
 		}
 	}
 
 	var percentThresholdBuyString = os.Getenv("PERCENT_THRESHOLD_BUY")
 	if percentThresholdBuyString != "" {
 		if v, err := strconv.ParseFloat(percentThresholdBuyString, 64); err == nil {
-			percentThresholdBuy = v
+			cfg.PercentThresholdBuy = v
 		} else {
-			log.Printf("Warning: invalid PERCENT_THRESHOLD_BUY: %v. Using default %.2f\n", err, percentThresholdBuy)
+			log.Printf("Warning: invalid PERCENT_THRESHOLD_BUY: %v. Using default %.2f\n", err, cfg.PercentThresholdBuy)
 		}
 	}
 
 	var percentThresholdSellString = os.Getenv("PERCENT_THRESHOLD_SELL")
 	if percentThresholdSellString != "" {
 		if v, err := strconv.ParseFloat(percentThresholdSellString, 64); err == nil {
-			percentThresholdSell = v
+			cfg.PercentThresholdSell = v
 		} else {
-			log.Printf("Warning: invalid PERCENT_THRESHOLD_SELL: %v. Using default %.2f\n", err, percentThresholdSell)
+			log.Printf("Warning: invalid PERCENT_THRESHOLD_SELL: %v. Using default %.2f\n", err, cfg.PercentThresholdSell)
 		}
 	}
 
 	var minQuantityString = os.Getenv("MIN_QUANTITY")
 	if minQuantityString != "" {
 		if v, err := strconv.ParseFloat(minQuantityString, 64); err == nil {
-			minQuantity = v
+			cfg.MinQuantity = v
+		} else {
+			log.Printf("Warning: invalid MIN_QUANTITY: %v. Using default %.2f\n", err, cfg.MinQuantity)
+		}
+	}
+
+	var buyQuoteAmountString = os.Getenv("BUY_QUOTE_AMOUNT")
+	if buyQuoteAmountString != "" {
+		if v, err := strconv.ParseFloat(buyQuoteAmountString, 64); err == nil && v >= 0 {
+			cfg.BuyQuoteAmount = v
+		} else {
+			log.Printf("Warning: invalid BUY_QUOTE_AMOUNT: %q. Using default %.2f\n", buyQuoteAmountString, cfg.BuyQuoteAmount)
+		}
+	}
+
+	var bracketTakeProfitString = os.Getenv("BRACKET_TAKE_PROFIT")
+	if bracketTakeProfitString != "" {
+		if v, err := strconv.ParseFloat(bracketTakeProfitString, 64); err == nil && v >= 0 {
+			cfg.BracketTakeProfit = v
+		} else {
+			log.Printf("Warning: invalid BRACKET_TAKE_PROFIT: %q. OCO brackets disabled\n", bracketTakeProfitString)
+		}
+	}
+
+	var bracketStopLossString = os.Getenv("BRACKET_STOP_LOSS")
+	if bracketStopLossString != "" {
+		if v, err := strconv.ParseFloat(bracketStopLossString, 64); err == nil && v >= 0 && v < 100 {
+			cfg.BracketStopLoss = v
+		} else {
+			log.Printf("Warning: invalid BRACKET_STOP_LOSS: %q. OCO brackets disabled\n", bracketStopLossString)
+		}
+	}
+
+	var bracketStopLimitGapString = os.Getenv("BRACKET_STOP_LIMIT_GAP")
+	if bracketStopLimitGapString != "" {
+		if v, err := strconv.ParseFloat(bracketStopLimitGapString, 64); err == nil && v >= 0 && v < 100 {
+			cfg.BracketStopLimitGap = v
+		} else {
+			log.Printf("Warning: invalid BRACKET_STOP_LIMIT_GAP: %q. Using default %.2f\n", bracketStopLimitGapString, cfg.BracketStopLimitGap)
+		}
+	}
+
+	var orderMaxAgeString = os.Getenv("ORDER_MAX_AGE")
+	if orderMaxAgeString != "" {
+		if v, err := time.ParseDuration(orderMaxAgeString); err == nil && v >= 0 {
+			cfg.OrderMaxAge = v
+		} else {
+			log.Printf("Warning: invalid ORDER_MAX_AGE: %q. Using default %s\n", orderMaxAgeString, cfg.OrderMaxAge)
+		}
+	}
+
+	// VALIDATE_ORDERS checks every order with /api/v3/order/test first, DRY_VALIDATE never places or cancels any
+	cfg.ValidateOrders = os.Getenv("VALIDATE_ORDERS") == "true"
+	cfg.DryValidate = os.Getenv("DRY_VALIDATE") == "true"
+	if cfg.DryValidate {
+		log.Println("🧪 Dry-validate mode: orders are validated but never placed")
+	}
+
+	api := binance.NewHttpRequest(apiKey, secretKey)
+	api.Signer = signer
+
+	// BINANCE_ENV picks the environment, BINANCE_API_URLS / BINANCE_STREAM_URLS override its hosts
+	endpoints, err := binance.EndpointsFor(os.Getenv("BINANCE_ENV"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if urls := splitList(os.Getenv("BINANCE_API_URLS")); len(urls) > 0 {
+		endpoints.REST = urls
+		endpoints.MarketData = nil
+	}
+	if urls := splitList(os.Getenv("BINANCE_STREAM_URLS")); len(urls) > 0 {
+		endpoints.Stream = urls
+	}
+	api.SetEndpoints(endpoints)
+	log.Printf("Binance endpoint: %s\n", endpoints.REST[0])
+
+	if quotes := splitList(os.Getenv("QUOTE_ASSETS")); len(quotes) > 0 {
+		api.QuoteAssets = nil
+		for _, quote := range quotes {
+			api.QuoteAssets = append(api.QuoteAssets, strings.ToUpper(quote))
+		}
+	}
+
+	// cost basis of coins deposited instead of bought
+	switch v := strings.ToLower(os.Getenv("DEPOSIT_COST_BASIS")); v {
+	case "":
+	case binance.CostBasisMarket, binance.CostBasisManual:
+		api.DepositCostBasis = v
+	default:
+		log.Printf("Warning: invalid DEPOSIT_COST_BASIS: %q (market, manual). Using %s\n", v, api.DepositCostBasis)
+	}
+	if items := splitList(os.Getenv("MANUAL_COST_BASIS")); len(items) > 0 {
+		api.ManualCostBasis = make(map[string]float64)
+		for _, item := range items {
+			asset, price, ok := strings.Cut(item, ":")
+			v, err := strconv.ParseFloat(strings.TrimSpace(price), 64)
+			if !ok || err != nil || v < 0 {
+				log.Printf("Warning: invalid MANUAL_COST_BASIS entry %q (ASSET:price)\n", item)
+				continue
+			}
+			api.ManualCostBasis[strings.ToUpper(strings.TrimSpace(asset))] = v
+		}
+	}
+	// coins sold off before startup that realized PnL should still cover
+	for _, asset := range splitList(os.Getenv("PNL_ASSETS")) {
+		api.PnLAssets = append(api.PnLAssets, strings.ToUpper(asset))
+	}
+	if v := os.Getenv("TRANSFER_HISTORY_START"); v != "" {
+		if start, err := time.Parse("2006-01-02", v); err == nil {
+			api.TransferHistoryStart = start
 		} else {
-			log.Printf("Warning: invalid MIN_QUANTITY: %v. Using default %.2f\n", err, minQuantity)
+			log.Printf("Warning: invalid TRANSFER_HISTORY_START: %q (YYYY-MM-DD). Using %s\n", v, api.TransferHistoryStart.Format("2006-01-02"))
 		}
 	}
 
-	api = binance.NewHttpRequest(apiKey, secretKey)
-	telegram = notifier.NewTelegramNotifier(tgToken, tgChatID)
+	// which lots a sale consumes for cost basis and realized PnL
+	switch v := strings.ToUpper(os.Getenv("LOT_METHOD")); v {
+	case "":
+	case binance.LotMethodFIFO, binance.LotMethodLIFO, binance.LotMethodHIFO:
+		api.LotMethod = v
+	default:
+		log.Printf("Warning: invalid LOT_METHOD: %q (FIFO, LIFO, HIFO). Using %s\n", v, api.LotMethod)
+	}
+
+	var recvWindowString = os.Getenv("RECV_WINDOW")
+	if recvWindowString != "" {
+		if v, err := strconv.Atoi(recvWindowString); err == nil && v > 0 && v <= 60000 {
+			api.RecvWindow = time.Duration(v) * time.Millisecond
+		} else {
+			log.Printf("Warning: invalid RECV_WINDOW: %q (1-60000 ms). Using Binance default\n", recvWindowString)
+		}
+	}
+	if err := api.SyncTime(); err != nil {
+		log.Printf("Warning: %v\n", err)
+	}
+
+	telegram := notifier.NewTelegramNotifier(tgToken, tgChatID)
+	autoTrader = trader.NewTrader(api, telegram, cfg)
+
+	// Create a context that is cancelled on SIGINT (Ctrl+C)
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
 
 	// --- Add flag ---
 	runNow := flag.Bool("now", false, "Run the job immediately without waiting for schedule")
@@ -330,21 +304,58 @@ func main() {
 
 	if *runNow {
 		fmt.Println("🚀 Running job immediately (--now)")
-		cronJob() // run once immediately
-		summarizeBalances()
+		autoTrader.CronJob(ctx) // run once immediately
+		autoTrader.SummarizeBalances(ctx)
 		return
 	}
 
+	// --- market data over websocket, REST is only the fallback ---
+	if os.Getenv("MARKET_STREAM") != "false" {
+		stream := binance.NewMarketStream(api, cfg.Interval, "1d")
+		stream.URLs = endpoints.Stream
+		autoTrader = trader.NewTrader(binance.NewStreamingExchange(api, stream), telegram, cfg)
+
+		// a price crossing PercentThreshold is checked right away, not on the next run
+		stream.OnPrice = autoTrader.HandlePrice
+		go func() {
+			if err := stream.Run(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("Market stream stopped: %v\n", err)
+			}
+		}()
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-autoTrader.EarlyChecks():
+					autoTrader.CronJob(ctx)
+				}
+			}
+		}()
+	}
+
+	// --- fills and balance changes over the user data stream ---
+	if os.Getenv("USER_STREAM") != "false" {
+		userStream := binance.NewUserStream(api)
+		userStream.OnExecutionReport = autoTrader.HandleExecutionReport
+		userStream.OnAccountPosition = autoTrader.HandleAccountPosition
+		go func() {
+			if err := userStream.Run(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("User stream stopped: %v\n", err)
+			}
+		}()
+	}
+
 	// --- default: cron schedule ---
 	c := cron.New()
 	// run every 5 minutes
-	_, err = c.AddFunc("@every 5m", cronJob)
+	_, err = c.AddFunc("@every 5m", func() { autoTrader.CronJob(ctx) })
 	if err != nil {
 		fmt.Println("❌ Cannot schedule job:", err)
 		os.Exit(1)
 	}
 	// run every day at 12:30 (12:30 PM) - summary of balances
-	_, err = c.AddFunc("30 12 * * *", summarizeBalances)
+	_, err = c.AddFunc("30 12 * * *", func() { autoTrader.SummarizeBalances(ctx) })
 	if err != nil {
 		fmt.Println("❌ Cannot schedule job:", err)
 		os.Exit(1)
@@ -355,11 +366,6 @@ func main() {
 	// --- end cron ---
 
 	// Setup Telegram bot
-	// Create a context that is cancelled on SIGINT (Ctrl+C)
-
-	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
-	defer cancel()
-
 	opts := []bot.Option{
 		bot.WithDefaultHandler(handler),
 	}
@@ -371,5 +377,9 @@ func main() {
 	log.Println("Bot started.")
 	b.Start(ctx)
 
+	// wait for running jobs to observe the cancelled context and return
+	<-c.Stop().Done()
+	log.Println("Cron jobs stopped.")
+
 	// select {} // block forever
 }
@@ -0,0 +1,155 @@
+package trader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+
+	"main.go/binance"
+)
+
+// bracket is an OCO order list resting on a held position
+type bracket struct {
+	orderListID  int64
+	averagePrice float64 // average price the legs were priced from
+	quantity     float64
+}
+
+// bracketsEnabled reports whether positions should be protected by OCO brackets.
+// OCO lists can't be sent to /api/v3/order/test, so DryValidate turns them off.
+func (c Config) bracketsEnabled() bool {
+	return c.BracketTakeProfit > 0 && c.BracketStopLoss > 0 && !c.DryValidate
+}
+
+// bracketQuantity returns the quantity locked in the bracket on symbol
+func (t *Trader) bracketQuantity(symbol string) float64 {
+	t.bracketsMu.Lock()
+	defer t.bracketsMu.Unlock()
+	if b := t.brackets[symbol]; b != nil {
+		return b.quantity
+	}
+	return 0
+}
+
+// maintainBracket keeps one OCO bracket on a held position, replacing it when
+// the average price has moved (e.g. after a DCA buy) or when it is no longer working.
+// No bracket is placed while price is already past one of its legs, as Binance
+// would reject it; a skipped or rejected bracket is reported once, not every cycle.
+func (t *Trader) maintainBracket(ctx context.Context, balance binance.AccountBalance, price float64) string {
+	if !t.cfg.bracketsEnabled() {
+		return ""
+	}
+
+	t.bracketsMu.Lock()
+	defer t.bracketsMu.Unlock()
+
+	req := binance.OCORequest{
+		Symbol:            balance.Symbol,
+		Side:              "SELL",
+		Quantity:          balance.Free,
+		TakeProfitPrice:   balance.AveragePrice * (1 + t.cfg.BracketTakeProfit/100),
+		StopPrice:         balance.AveragePrice * (1 - t.cfg.BracketStopLoss/100),
+		ListClientOrderID: newClientOrderID("oco", balance.Symbol),
+		TakeProfitOrderID: newClientOrderID("tp", balance.Symbol),
+		StopOrderID:       newClientOrderID("sl", balance.Symbol),
+	}
+	req.StopLimitPrice = req.StopPrice * (1 - t.cfg.BracketStopLimitGap/100)
+
+	b := t.brackets[balance.Symbol]
+	if b != nil {
+		list, err := t.exchange.GetOrderListContext(ctx, b.orderListID)
+		if err != nil {
+			log.Printf("[%s] OCO status error: %v\n", balance.Symbol, err)
+			return ""
+		}
+		if list.Done() {
+			delete(t.brackets, balance.Symbol)
+			b = nil
+		} else if math.Abs(b.averagePrice-balance.AveragePrice) <= balance.AveragePrice*1e-6 {
+			return "" // still protecting the position at the right prices
+		}
+	}
+
+	if price <= req.StopPrice || price >= req.TakeProfitPrice {
+		// keep a working bracket rather than replace it with one that can't be placed
+		return t.bracketProblem(balance.Symbol, "outside", fmt.Sprintf("🛡️ OCO bracket for #%s skipped: price %.8f is outside %.8f - %.8f.",
+			balance.Symbol, price, req.StopPrice, req.TakeProfitPrice))
+	}
+
+	if b != nil {
+		canceled, err := t.exchange.CancelOrderListContext(ctx, balance.Symbol, b.orderListID)
+		if err != nil {
+			log.Printf("[%s] OCO cancel error: %v\n", balance.Symbol, err)
+			return ""
+		}
+		// a partly filled leg has already sold part of the locked quantity
+		req.Quantity += b.unfilled(canceled)
+		delete(t.brackets, balance.Symbol)
+	}
+
+	list, err := t.exchange.PlaceOCOContext(ctx, req)
+	if err != nil {
+		log.Printf("OCO order error #%s: %v\n", balance.Symbol, err)
+		var filterErr *binance.FilterError
+		if errors.As(err, &filterErr) {
+			return "" // position too small to protect, don't alert every cycle
+		}
+		msg := strings.TrimPrefix(orderErrorMessage("OCO", err), "\n\n")
+		return t.bracketProblem(balance.Symbol, msg, msg)
+	}
+	delete(t.bracketProblems, balance.Symbol)
+	t.brackets[balance.Symbol] = &bracket{
+		orderListID:  list.OrderListID,
+		averagePrice: balance.AveragePrice,
+		quantity:     req.Quantity,
+	}
+
+	return fmt.Sprintf("🛡️ *OCO bracket for #%s* \nQuantity: %.8f \nTake-Profit: %.8f \nStop: %.8f (limit %.8f)",
+		balance.Symbol, req.Quantity, req.TakeProfitPrice, req.StopPrice, req.StopLimitPrice)
+}
+
+// bracketProblem returns msg the first time a bracket on symbol can't be
+// placed for reason and "" while the same problem persists.
+// Must be called with bracketsMu held.
+func (t *Trader) bracketProblem(symbol, reason, msg string) string {
+	if msg == "" || t.bracketProblems[symbol] == reason {
+		return ""
+	}
+	t.bracketProblems[symbol] = reason
+	return msg
+}
+
+// releaseBracket cancels the bracket on symbol so its quantity can be sold
+// and returns how much of it was freed
+func (t *Trader) releaseBracket(ctx context.Context, symbol string) float64 {
+	t.bracketsMu.Lock()
+	defer t.bracketsMu.Unlock()
+
+	b := t.brackets[symbol]
+	if b == nil {
+		return 0
+	}
+	delete(t.brackets, symbol)
+
+	list, err := t.exchange.CancelOrderListContext(ctx, symbol, b.orderListID)
+	if err != nil {
+		// most likely a leg already filled and the list is done
+		log.Printf("[%s] OCO cancel error: %v\n", symbol, err)
+		return 0
+	}
+
+	return b.unfilled(list)
+}
+
+// unfilled returns the bracket quantity that neither leg of the canceled
+// list executed
+func (b *bracket) unfilled(list *binance.OrderList) float64 {
+	executed := 0.0
+	for _, order := range list.Orders {
+		executed = math.Max(executed, order.ExecutedQty)
+	}
+	return b.quantity - executed
+}
@@ -0,0 +1,54 @@
+package trader
+
+import (
+	"log"
+
+	"main.go/binance"
+)
+
+// watchBalances remembers the average price of every tradable holding, so
+// streamed prices can be checked against PercentThreshold between cycles
+func (t *Trader) watchBalances(balances []binance.AccountBalance) {
+	t.watchMu.Lock()
+	defer t.watchMu.Unlock()
+
+	t.watch = make(map[string]float64, len(balances))
+	for _, balance := range balances {
+		if balance.Symbol != "" && balance.AveragePrice > 0 {
+			t.watch[balance.Symbol] = balance.AveragePrice
+		}
+	}
+}
+
+// HandlePrice requests an early trading cycle when a streamed price moves a
+// holding out of the PercentThreshold band around its average price, instead
+// of waiting for the next scheduled run. Only the crossing triggers; a price
+// that stays outside the band is left to the regular cycles.
+func (t *Trader) HandlePrice(symbol string, price float64) {
+	t.watchMu.Lock()
+	averagePrice, ok := t.watch[symbol]
+	if !ok {
+		t.watchMu.Unlock()
+		return
+	}
+	change := (price - averagePrice) / averagePrice * 100
+	outside := change <= -t.cfg.PercentThreshold || change >= t.cfg.PercentThreshold
+	crossed := outside && !t.outside[symbol]
+	t.outside[symbol] = outside
+	t.watchMu.Unlock()
+
+	if !crossed {
+		return
+	}
+	select {
+	case t.early <- struct{}{}:
+		log.Printf("[%s] Price moved %.2f%% from the average, checking early\n", symbol, change)
+	default: // a check is already queued
+	}
+}
+
+// EarlyChecks delivers the early cycles requested by HandlePrice; run
+// CronJob for each of them
+func (t *Trader) EarlyChecks() <-chan struct{} {
+	return t.early
+}
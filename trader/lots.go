@@ -0,0 +1,43 @@
+package trader
+
+import (
+	"context"
+	"fmt"
+	"log"
+)
+
+// maxReportedSales is how many of the latest sales ReportLots lists per symbol
+const maxReportedSales = 5
+
+// ReportLots sends the open lots of every holding and its latest sales with
+// their realized PnL to Telegram
+func (t *Trader) ReportLots(ctx context.Context) {
+	balances, err := t.exchange.GetAccountBalancesContext(ctx)
+	if err != nil {
+		log.Println("Error getting balances:", err)
+		return
+	}
+
+	msg := "📦 *Open Lots and Realized PnL:*\n"
+	for _, balance := range balances {
+		if len(balance.OpenLots) == 0 && len(balance.Sales) == 0 {
+			continue
+		}
+		msg += fmt.Sprintf("\n[#%s] realized: %.2f USDT\n", balance.Asset, balance.RealizedPnL)
+		for _, lot := range balance.OpenLots {
+			msg += fmt.Sprintf("  lot %s: %.8f @ %.8f\n", lot.Time.Format("2006-01-02"), lot.Qty, lot.Price)
+		}
+
+		sales := balance.Sales
+		if len(sales) > maxReportedSales {
+			sales = sales[len(sales)-maxReportedSales:]
+		}
+		for _, s := range sales {
+			msg += fmt.Sprintf("  sold %s: %.8f for %.2f (PnL %+.2f)\n", s.Time.Format("2006-01-02"), s.Qty, s.Proceeds, s.RealizedPnL)
+		}
+	}
+
+	if err := t.notifier.Send(msg); err != nil {
+		log.Printf("Telegram send error: %v\n", err)
+	}
+}
@@ -0,0 +1,175 @@
+package trader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"main.go/binance"
+)
+
+// clientOrderPrefix marks the orders placed by this bot
+const clientOrderPrefix = "atb-"
+
+// newClientOrderID returns the clientOrderId for an order of kind on symbol.
+// It is deterministic within a minute, so retrying the same decision reuses
+// the ID; binance.ClientOrderID keeps it within Binance's length limit.
+func newClientOrderID(kind, symbol string) string {
+	return binance.ClientOrderID(clientOrderPrefix, kind, symbol, time.Now())
+}
+
+// isOwnOrder reports whether the order was placed by this bot
+func isOwnOrder(order *binance.OrderResult) bool {
+	return strings.HasPrefix(order.ClientOrderID, clientOrderPrefix)
+}
+
+// reconcileOrders cancels the bot's resting orders that it no longer tracks:
+// brackets left over from a restart or a failed replace, duplicate orders on
+// the same symbol and side, and orders older than Config.OrderMaxAge.
+// Orders placed by hand are never touched. In DryValidate mode the orders
+// are only reported: they may be live protection from an earlier run.
+func (t *Trader) reconcileOrders(ctx context.Context) {
+	orders, err := t.exchange.GetOpenOrdersContext(ctx, "")
+	if err != nil {
+		log.Println("Open orders error:", err)
+		return
+	}
+
+	// newest first, so the first order seen per symbol/side is the one kept
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i].TransactTime.After(orders[j].TransactTime)
+	})
+
+	t.bracketsMu.Lock()
+	tracked := make(map[int64]bool, len(t.brackets))
+	for _, b := range t.brackets {
+		tracked[b.orderListID] = true
+	}
+	t.bracketsMu.Unlock()
+
+	canceledLists := make(map[int64]bool)
+	seen := make(map[string]bool)
+	for _, order := range orders {
+		if !isOwnOrder(order) {
+			continue
+		}
+
+		if order.OrderListID >= 0 {
+			if tracked[order.OrderListID] || canceledLists[order.OrderListID] {
+				continue
+			}
+			canceledLists[order.OrderListID] = true
+			if t.cfg.DryValidate {
+				log.Printf("[%s] 🧪 Would cancel untracked OCO %d (not canceled)\n", order.Symbol, order.OrderListID)
+				continue
+			}
+			log.Printf("[%s] Canceling untracked OCO %d\n", order.Symbol, order.OrderListID)
+			if _, err := t.exchange.CancelOrderListContext(ctx, order.Symbol, order.OrderListID); err != nil {
+				log.Printf("[%s] OCO cancel error: %v\n", order.Symbol, err)
+			}
+			continue
+		}
+
+		key := order.Symbol + "/" + order.Side
+		stale := t.cfg.OrderMaxAge > 0 && time.Since(order.TransactTime) > t.cfg.OrderMaxAge
+		if !stale && !seen[key] {
+			seen[key] = true
+			continue
+		}
+		if t.cfg.DryValidate {
+			log.Printf("[%s] 🧪 Would cancel %s %s order %d (stale: %t, not canceled)\n", order.Symbol, order.Type, order.Side, order.OrderID, stale)
+			continue
+		}
+		log.Printf("[%s] Canceling %s %s order %d (stale: %t)\n", order.Symbol, order.Type, order.Side, order.OrderID, stale)
+		if _, err := t.exchange.CancelOrderContext(ctx, order.Symbol, order.OrderID); err != nil {
+			log.Printf("[%s] Cancel error: %v\n", order.Symbol, err)
+		}
+	}
+}
+
+// orderStatusValidated is the status of an order that was only validated
+const orderStatusValidated = "VALIDATED"
+
+// commissionMessage reports the commission estimated by order validation
+// for an order worth notional; the actual fee is used once it has filled
+func commissionMessage(order *binance.OrderResult, notional float64) string {
+	if order.Commission == nil {
+		return ""
+	}
+	if order.CummulativeQuoteQty > 0 {
+		notional = order.CummulativeQuoteQty
+	}
+	msg := fmt.Sprintf("\nCommission: %.4f%% ≈ %.4f USDT", (order.Commission.Taker+order.Commission.TaxTaker)*100, order.Commission.Estimate(notional, false))
+	if order.Commission.DiscountAsset != "" {
+		msg += fmt.Sprintf(" (%.0f%% off in %s)", order.Commission.Discount*100, order.Commission.DiscountAsset)
+	}
+	return msg
+}
+
+// placeOrder sends req under a bot clientOrderId of kind. When Binance can't
+// tell whether the order went through, the ID is remembered so the next
+// cycle resolves it before trading the symbol again.
+// In DryValidate mode the order is only validated and a result with
+// orderStatusValidated is returned.
+func (t *Trader) placeOrder(ctx context.Context, kind string, req binance.OrderRequest) (*binance.OrderResult, error) {
+	req.ClientOrderID = newClientOrderID(kind, req.Symbol)
+	if t.cfg.DryValidate {
+		commission, err := t.exchange.TestOrderContext(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return &binance.OrderResult{
+			Symbol:        req.Symbol,
+			ClientOrderID: req.ClientOrderID,
+			Side:          req.Side,
+			Type:          req.Type,
+			Status:        orderStatusValidated,
+			Commission:    commission,
+		}, nil
+	}
+
+	req.Validate = t.cfg.ValidateOrders
+	order, err := t.exchange.CreateOrderContext(ctx, req)
+
+	var unknownErr *binance.OrderUnknownError
+	if errors.As(err, &unknownErr) {
+		t.pendingMu.Lock()
+		t.pending[req.Symbol] = unknownErr.ClientOrderID
+		t.pendingMu.Unlock()
+	}
+	return order, err
+}
+
+// resolvePending looks up an order on symbol whose outcome was unknown.
+// It returns settled=false while the symbol must not be traded this cycle,
+// either because the order turned out to be placed or the lookup failed.
+func (t *Trader) resolvePending(ctx context.Context, symbol string) (msg string, settled bool) {
+	t.pendingMu.Lock()
+	clientOrderID, ok := t.pending[symbol]
+	t.pendingMu.Unlock()
+	if !ok {
+		return "", true
+	}
+
+	order, err := t.exchange.GetOrderByClientIDContext(ctx, symbol, clientOrderID)
+	if err != nil && !errors.Is(err, binance.ErrNoSuchOrder) {
+		log.Printf("[%s] Pending order %s still unknown: %v\n", symbol, clientOrderID, err)
+		return "", false
+	}
+
+	t.pendingMu.Lock()
+	delete(t.pending, symbol)
+	t.pendingMu.Unlock()
+
+	if err != nil {
+		log.Printf("[%s] Pending order %s was never placed\n", symbol, clientOrderID)
+		return "", true
+	}
+	log.Printf("[%s] Pending order %s was placed: %s\n", symbol, clientOrderID, order.Status)
+	return fmt.Sprintf("🔎 *Order confirmed for #%s* \n%s %s: %.8f @ %.8f (%s)",
+		symbol, order.Type, order.Side, order.ExecutedQty, order.AveragePrice(), order.Status), false
+}
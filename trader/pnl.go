@@ -0,0 +1,77 @@
+package trader
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"main.go/binance"
+)
+
+// realizedPnL is the PnL of sales over the report periods, in USDT
+type realizedPnL struct {
+	Day      float64 // since local midnight
+	Month    float64 // since the first of the month
+	Lifetime float64
+}
+
+func (r *realizedPnL) add(o realizedPnL) {
+	r.Day += o.Day
+	r.Month += o.Month
+	r.Lifetime += o.Lifetime
+}
+
+// realizedSince sums the realized PnL of sales per report period
+func realizedSince(sales []binance.Sale, now time.Time) realizedPnL {
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+
+	var r realizedPnL
+	for _, s := range sales {
+		r.Lifetime += s.RealizedPnL
+		if !s.Time.Before(month) {
+			r.Month += s.RealizedPnL
+		}
+		if !s.Time.Before(day) {
+			r.Day += s.RealizedPnL
+		}
+	}
+	return r
+}
+
+// closedPositions lists the realized PnL of traded assets that are no longer
+// held, rebuilt from their full history so it survives restarts, and returns
+// it together with their total
+func (t *Trader) closedPositions(ctx context.Context, balances []binance.AccountBalance, now time.Time) (string, realizedPnL) {
+	var total realizedPnL
+	assets, err := t.exchange.GetTradedAssetsContext(ctx)
+	if err != nil {
+		log.Println("Traded assets error:", err)
+		return "", total
+	}
+
+	held := make(map[string]bool, len(balances))
+	for _, balance := range balances {
+		held[balance.Asset] = true
+	}
+
+	msg := ""
+	for _, asset := range assets {
+		if held[asset] {
+			continue
+		}
+		ledger, err := t.exchange.GetLotLedgerContext(ctx, asset)
+		if err != nil {
+			log.Printf("[%s] Cannot compute realized PnL: %v\n", asset, err)
+			continue
+		}
+		if len(ledger.Sales) == 0 {
+			continue
+		}
+		r := realizedSince(ledger.Sales, now)
+		total.add(r)
+		msg += fmt.Sprintf("[#%s]: closed - Realized: %.2f\n", asset, r.Lifetime)
+	}
+	return msg, total
+}
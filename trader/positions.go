@@ -0,0 +1,37 @@
+package trader
+
+import (
+	"time"
+
+	"main.go/binance"
+)
+
+// HandleAccountPosition keeps the balances reported by the user data stream,
+// so a fill between the cycle's balance snapshot and an order (e.g. a
+// bracket leg) is taken into account
+func (t *Trader) HandleAccountPosition(positions []binance.AssetPosition) {
+	t.positionsMu.Lock()
+	defer t.positionsMu.Unlock()
+	for _, p := range positions {
+		if p.UpdatedAt.After(t.positions[p.Asset].UpdatedAt) {
+			t.positions[p.Asset] = p
+		}
+	}
+}
+
+// freshBalance returns balance with Free and Locked taken from the streamed
+// position when that is newer than the snapshot taken at snapshotAt
+func (t *Trader) freshBalance(balance binance.AccountBalance, snapshotAt time.Time) binance.AccountBalance {
+	t.positionsMu.Lock()
+	p, ok := t.positions[balance.Asset]
+	t.positionsMu.Unlock()
+	if !ok || !p.UpdatedAt.After(snapshotAt) {
+		return balance
+	}
+
+	balance.Free = p.Free
+	balance.Locked = p.Locked
+	balance.Total = p.Free + p.Locked + balance.Earn
+	balance.TotalUSDT = balance.Total * balance.AveragePrice
+	return balance
+}
@@ -0,0 +1,453 @@
+package trader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sync"
+	"time"
+
+	"main.go/binance"
+	"main.go/utils"
+)
+
+// Config holds the strategy thresholds used by the trading loop
+type Config struct {
+	Interval             string  // interval for klines, e.g. 4h
+	PercentThreshold     float64 // percentage change threshold for alerts
+	PercentThresholdBuy  float64 // percentage change threshold buy for alerts
+	PercentThresholdSell float64 // percentage change threshold sell for alerts
+	MinQuantity          float64 // minimum quantity to trade
+	BuyQuoteAmount       float64 // USDT spent per DCA buy; 0 buys MinQuantity units instead
+
+	BracketTakeProfit   float64 // OCO take-profit, % above the average price; 0 disables brackets
+	BracketStopLoss     float64 // OCO stop trigger, % below the average price; 0 disables brackets
+	BracketStopLimitGap float64 // stop-limit price, % below the stop trigger
+
+	OrderMaxAge time.Duration // the bot's resting orders older than this are canceled; 0 keeps them
+
+	ValidateOrders bool // run every order through /api/v3/order/test before placing it
+	DryValidate    bool // only validate orders, never place or cancel them
+}
+
+// DefaultConfig returns the built-in strategy thresholds
+func DefaultConfig() Config {
+	return Config{
+		Interval:             "4h",
+		PercentThreshold:     10.0,
+		PercentThresholdBuy:  10.0,
+		PercentThresholdSell: 15.0,
+		MinQuantity:          5.0,
+		BuyQuoteAmount:       10.0,
+
+		BracketStopLimitGap: 0.5,
+
+		OrderMaxAge: 24 * time.Hour,
+	}
+}
+
+// Notifier delivers trade alerts and summaries, e.g. notifier.TelegramNotifier
+type Notifier interface {
+	Send(message string) error
+}
+
+// Trader runs the auto-trade strategy against an Exchange
+type Trader struct {
+	exchange binance.Exchange
+	notifier Notifier
+	cfg      Config
+
+	bracketsMu      sync.Mutex
+	brackets        map[string]*bracket // resting OCO bracket by symbol
+	bracketProblems map[string]string   // last reported reason a bracket couldn't be placed, by symbol
+
+	pendingMu sync.Mutex
+	pending   map[string]string // clientOrderId of an order in unknown state, by symbol
+
+	cycleMu sync.Mutex // one trading cycle at a time
+
+	positionsMu sync.Mutex
+	positions   map[string]binance.AssetPosition // latest streamed balance, by asset
+
+	watchMu sync.Mutex
+	watch   map[string]float64 // average price of each tradable holding, by symbol
+	outside map[string]bool    // whether the last streamed price was past PercentThreshold
+	early   chan struct{}      // early cycle requested by HandlePrice
+}
+
+// NewTrader creates a new Trader for the given exchange and notifier
+func NewTrader(exchange binance.Exchange, notifier Notifier, cfg Config) *Trader {
+	return &Trader{
+		exchange: exchange,
+		notifier: notifier,
+		cfg:      cfg,
+		brackets: make(map[string]*bracket),
+		pending:  make(map[string]string),
+		watch:    make(map[string]float64),
+		outside:  make(map[string]bool),
+		early:    make(chan struct{}, 1),
+
+		bracketProblems: make(map[string]string),
+		positions:       make(map[string]binance.AssetPosition),
+	}
+}
+
+// =================== Worker ======================
+func (t *Trader) checkSignal(ctx context.Context, symbol string, change float64) (*utils.PredictResult, error) {
+	klines, err := t.exchange.GetKlinesContext(ctx, symbol, t.cfg.Interval, 200)
+	if err != nil {
+		log.Printf("GetKlines failed: %v", err)
+		return nil, err
+	}
+	if len(klines) < 29 {
+		log.Printf("not enough klines for RSI: have=%d", len(klines))
+		return nil, errors.New("not enough klines for RSI")
+	}
+
+	// collect closes in chronological order
+	closes := make([]float64, len(klines))
+	for i := range klines {
+		closes[i] = klines[i].Close
+	}
+
+	prediction, err := utils.PredictNextPrice(closes)
+	if err != nil {
+		fmt.Println("❌ Error:", err)
+		return nil, err
+	}
+	// Fetch daily high (1D interval)
+	dayKlines, err := t.exchange.GetKlinesContext(ctx, symbol, "1d", 1)
+	if err != nil {
+		log.Printf("GetKlines 1d failed: %v", err)
+	} else if len(dayKlines) > 0 {
+		prediction.DayHigh = dayKlines[0].High
+		prediction.DayLow = dayKlines[0].Low
+	}
+
+	return prediction, nil
+}
+
+func (t *Trader) autoTrade(ctx context.Context, balance binance.AccountBalance, price float64) string {
+	// an earlier order may have gone through without us knowing; settle it first
+	if msg, settled := t.resolvePending(ctx, balance.Symbol); !settled {
+		return msg
+	}
+
+	msg := ""
+
+	// value everything held, including Earn; only Free can be sold
+	currentValueUSDT := price * balance.Total
+	pnlUSDT := currentValueUSDT - balance.TotalUSDT
+	profitOrLoss := fmt.Sprintf("Loss: %.2f USDT", pnlUSDT)
+	if pnlUSDT > 0 {
+		profitOrLoss = fmt.Sprintf("Profit: %.2f USDT", pnlUSDT)
+	}
+	if balance.Commission > 0 {
+		profitOrLoss += fmt.Sprintf(" (fees paid: %.2f USDT)", balance.Commission)
+	}
+	change := (price - balance.AveragePrice) / balance.AveragePrice * 100
+
+	fmt.Printf("[%s] Qty: %.8f | Entry Price: %.8f | Average Price: %.8f | Current: %.8f | Total: %.8f | PnL: %.8f (%.2f%%)\n",
+		balance.Symbol,
+		balance.Free,
+		balance.CostPrice,
+		balance.AveragePrice,
+		price,
+		balance.TotalUSDT,
+		pnlUSDT,
+		change)
+
+	if change > -t.cfg.PercentThreshold && change < t.cfg.PercentThreshold {
+		return t.maintainBracket(ctx, balance, price) // no significant change, hold
+	}
+
+	prediction, err := t.checkSignal(ctx, balance.Symbol, change)
+	if err != nil {
+		fmt.Println("❌ Error:", err)
+		return msg
+	}
+
+	msg += fmt.Sprintf("🚀🚀🚀 *Auto-Trade for: #%s * \nPnL: %.2f%% (%.8f → %.8f)\n%s\nSignal: *%s* \nQuantity: %.8f  \nEntry Price: %.8f \nAverage Price: %.8f \nCurrent Price: %.8f \nHigh:  %.8f - Low: %.8f  \nNext Price: %.8f (%+.2f%%)",
+		balance.Symbol,
+		change,
+		balance.AveragePrice,
+		price,
+		profitOrLoss,
+		prediction.Signal,
+		balance.Free,
+		balance.CostPrice,
+		balance.AveragePrice,
+		price,
+		prediction.DayHigh,
+		prediction.DayLow,
+		prediction.NextPrice,
+		prediction.ChangePct)
+	if change <= -t.cfg.PercentThreshold {
+		results, _ := utils.CalculateDCA(balance.Symbol, price, balance.Free, balance.AveragePrice)
+		fmt.Printf("📊 DCA Strategy for %s\n", balance.Symbol)
+
+		msg += fmt.Sprintf("\n\n📊 DCA Strategy for #%s\n", balance.Symbol)
+		for _, r := range results {
+			fmt.Printf("🎯 Target Avg: %.2f USDT |  Drop: %.2f%% | Buy: %.1f | Total: %.1f | Cost: %.2f USDT\n",
+				r.TargetAvg, r.DropPct, r.BuyQty, r.NewTotal, r.USDTSpent)
+
+			msg += fmt.Sprintf("🎯 Target Avg: %.2f USDT | Buy: %.1f | Total: %.1f | Cost: %.2f USDT\n", r.TargetAvg, r.BuyQty, r.NewTotal, r.USDTSpent)
+		}
+	}
+
+	// quantity resting in our own OCO bracket can be sold too
+	held := balance.Free + t.bracketQuantity(balance.Symbol)
+	traded := false
+
+	if (change > t.cfg.PercentThresholdSell && held >= t.cfg.MinQuantity) &&
+		(price >= prediction.DayHigh || prediction.Signal == "SELL") {
+		traded = true
+		qty := t.cfg.MinQuantity
+		if balance.Free < qty {
+			// only what the cancel actually freed can be sold: a leg may have
+			// filled in the meantime, or the cancel failed
+			qty = math.Min(qty, balance.Free+t.releaseBracket(ctx, balance.Symbol))
+		}
+		if qty <= 0 {
+			log.Printf("[%s] Sell skipped: nothing free after releasing the bracket\n", balance.Symbol)
+			return msg + "\n\n⚠️ Sell order skipped: the OCO bracket could not be released."
+		}
+
+		order, err := t.placeOrder(ctx, "sell", binance.OrderRequest{
+			Symbol:   balance.Symbol,
+			Side:     "SELL",
+			Type:     binance.OrderTypeMarket,
+			Quantity: qty,
+		})
+		if err != nil {
+			log.Printf("Sell order error #%s: %v\n", balance.Symbol, err)
+			return msg + orderErrorMessage("Sell", err)
+		}
+
+		if order.Status == orderStatusValidated {
+			msg += fmt.Sprintf("\n\n🧪 Partial Take-Profit validated: Sell %.8f units (not placed).", qty)
+		} else {
+			msg += fmt.Sprintf("\n\nPartial Take-Profit: Sold %.1f units @ %.8f.", order.ExecutedQty, order.AveragePrice())
+		}
+		msg += commissionMessage(order, qty*price)
+	}
+
+	if prediction.Signal == "BUY" && change <= -t.cfg.PercentThresholdBuy {
+		traded = true
+		req := binance.OrderRequest{Symbol: balance.Symbol, Side: "BUY", Type: binance.OrderTypeMarket}
+		if t.cfg.BuyQuoteAmount > 0 {
+			req.QuoteOrderQty = t.cfg.BuyQuoteAmount
+		} else {
+			req.Quantity = t.cfg.MinQuantity
+		}
+		order, err := t.placeOrder(ctx, "buy", req)
+		if err != nil {
+			log.Printf("Buy order error %s: %v\n", balance.Symbol, err)
+			return msg + orderErrorMessage("Buy", err)
+		}
+		notional := req.QuoteOrderQty + req.Quantity*price
+		if order.Status == orderStatusValidated {
+			msg += fmt.Sprintf("\n\n🧪 DCA Buy validated: %.2f USDT (not placed).", notional)
+		} else {
+			msg += fmt.Sprintf("\n\nDCA Buy Order: Bought %.8f units @ %.8f for %.2f USDT.", order.ExecutedQty, order.AveragePrice(), order.CummulativeQuoteQty)
+		}
+		msg += commissionMessage(order, notional)
+	}
+
+	// holding: protect the position until the next cycle; after a trade the
+	// bracket is re-placed once the new average price is known
+	if !traded {
+		if bracketMsg := t.maintainBracket(ctx, balance, price); bracketMsg != "" {
+			msg += "\n\n" + bracketMsg
+		}
+	}
+
+	return msg
+}
+
+// priceSnapshot prices every tradable balance with a single batch request.
+// A symbol Binance no longer knows is missing from the result and skipped.
+func (t *Trader) priceSnapshot(ctx context.Context, balances []binance.AccountBalance) (map[string]float64, error) {
+	symbols := make([]string, 0, len(balances))
+	for _, balance := range balances {
+		if balance.Symbol != "" {
+			symbols = append(symbols, balance.Symbol)
+		}
+	}
+	return t.exchange.GetPricesContext(ctx, symbols)
+}
+
+// orderErrorMessage renders an order failure for Telegram according to its class
+func orderErrorMessage(side string, err error) string {
+	var filterErr *binance.FilterError
+	var apiErr *binance.APIError
+	var unknownErr *binance.OrderUnknownError
+	switch {
+	case errors.As(err, &unknownErr):
+		return fmt.Sprintf("\n\n⚠️ %s order state unknown (%s), checking again next cycle.", side, unknownErr.ClientOrderID)
+	case errors.As(err, &filterErr):
+		return fmt.Sprintf("\n\n⚠️ %s order skipped (%s): %s", side, filterErr.Filter, filterErr.Reason)
+	case binance.IsInsufficientBalance(err):
+		return fmt.Sprintf("\n\n⚠️ %s order skipped: insufficient balance.", side)
+	case errors.Is(err, context.Canceled), errors.Is(err, binance.ErrTimestampOutsideRecvWindow), errors.Is(err, binance.ErrTooManyRequests):
+		return "" // transient, retried on the next cycle
+	case errors.Is(err, binance.ErrInvalidAPIKey), errors.Is(err, binance.ErrInvalidSignature):
+		return fmt.Sprintf("\n\n❌ %s order failed: check API key permissions (%s).", side, err)
+	case errors.As(err, &apiErr):
+		return fmt.Sprintf("\n\n❌ %s order rejected by Binance (%d): %s", side, apiErr.Code, apiErr.Msg)
+	}
+	return ""
+}
+
+// CronJob checks every holding and runs the auto-trade strategy on it.
+// It stops early once ctx is cancelled and is skipped while another cycle runs.
+func (t *Trader) CronJob(ctx context.Context) {
+	if !t.cycleMu.TryLock() {
+		log.Println("Trading cycle already running, skipped.")
+		return
+	}
+	defer t.cycleMu.Unlock()
+
+	// cancel stale and duplicate resting orders first, so the balances
+	// below include what they had locked
+	t.reconcileOrders(ctx)
+
+	snapshotAt := time.Now()
+	balances, err := t.exchange.GetAccountBalancesContext(ctx)
+	if err != nil {
+		log.Println("Error getting balances:", err)
+		return
+	}
+	t.watchBalances(balances)
+
+	log.Println("📊 Checking Account Balances:")
+
+	prices, err := t.priceSnapshot(ctx, balances)
+	if err != nil {
+		log.Println("Price error:", err)
+		return
+	}
+
+	for _, balance := range balances {
+		if ctx.Err() != nil {
+			log.Println("Shutting down, trading cycle aborted.")
+			return
+		}
+		// nothing to trade against
+		if balance.Symbol == "" {
+			continue
+		}
+		// if we couldn't compute buy price from trade history, skip
+		if balance.AveragePrice <= 0 {
+			log.Printf("[%s] No AveragePrice from account history (Qty: %.8f). Skipping.\n", balance.Asset, balance.Total)
+			continue
+		}
+		price, ok := prices[balance.Symbol]
+		if !ok {
+			log.Printf("[%s] No price. Skipping.\n", balance.Symbol)
+			continue
+		}
+		msg := t.autoTrade(ctx, t.freshBalance(balance, snapshotAt), price)
+		if msg != "" {
+			if err := t.notifier.Send(msg); err != nil {
+				log.Printf("Telegram send error: %v\n", err)
+			}
+			log.Printf("Telegram message sent for %s\n", balance.Symbol)
+		}
+	}
+}
+
+// SummarizeBalances sends a PnL summary of all holdings to Telegram
+func (t *Trader) SummarizeBalances(ctx context.Context) {
+	balances, err := t.exchange.GetAccountBalancesContext(ctx)
+	if err != nil {
+		log.Println("Error getting balances:", err)
+		return
+	}
+
+	log.Println("📊 Account Balances Summary:")
+	prices, err := t.priceSnapshot(ctx, balances)
+	if err != nil {
+		log.Println("Price error:", err)
+		return
+	}
+	now := time.Now()
+	msg := "📊 *Account Balances Summary:*\n\n"
+	totalUSDT := 0.0
+	totalCurrentUSDT := 0.0
+	totalProfitLoss := 0.0
+	var totalRealized realizedPnL
+	for _, balance := range balances {
+		realized := realizedSince(balance.Sales, now)
+		totalRealized.add(realized)
+		if balance.Symbol == "" {
+			msg += fmt.Sprintf("[#%s]: %.4f - no USDT market\n", balance.Asset, balance.Total)
+			continue
+		}
+		price, ok := prices[balance.Symbol]
+		if !ok {
+			log.Printf("[%s] No price.\n", balance.Symbol)
+		}
+
+		if balance.TotalUSDT > 0 {
+			currentValueUSDT := price * balance.Total
+			pnlUSDT := currentValueUSDT - balance.TotalUSDT
+			change := (price - balance.AveragePrice) / balance.AveragePrice * 100
+			totalUSDT += balance.TotalUSDT
+			totalCurrentUSDT += currentValueUSDT
+			totalProfitLoss += pnlUSDT
+			fmt.Printf("[%s]: Qty: %.8f | Avg Price: %.8f | Current Price: %.8f | Total: %.2f USDT. | %.2f PNL: %.2f (%.2f%%) | Realized: %.2f\n",
+				balance.Symbol, balance.Total, balance.AveragePrice, price, balance.TotalUSDT, currentValueUSDT, pnlUSDT, change, realized.Lifetime)
+			msg += fmt.Sprintf("[#%s]: %.4f - Avg: %.4f - PnL: %.2f (%.2f%%)\n",
+				balance.Symbol, balance.Free, balance.AveragePrice, pnlUSDT, change)
+			if realized.Lifetime != 0 {
+				msg += fmt.Sprintf("  Realized: %.2f (today %.2f)\n", realized.Lifetime, realized.Day)
+			}
+			if balance.Earn > 0 {
+				msg += fmt.Sprintf("  + %.4f in Simple Earn\n", balance.Earn)
+			}
+			if balance.QtyMismatch() {
+				msg += fmt.Sprintf("⚠️ history accounts for %.4f of %.4f\n", balance.LedgerQty, balance.Total)
+			}
+		}
+	}
+	closedMsg, closedRealized := t.closedPositions(ctx, balances, now)
+	msg += closedMsg
+	totalRealized.add(closedRealized)
+
+	totalChange := (totalCurrentUSDT - totalUSDT) / totalUSDT * 100
+	fmt.Printf("Total Portfolio Value: %.2f USDT. Current: %.2f. Unrealized PnL: %.2f (%.2f%%)\n", totalUSDT, totalCurrentUSDT, totalProfitLoss, totalChange)
+	fmt.Printf("Realized PnL: today %.2f | month %.2f | lifetime %.2f USDT\n", totalRealized.Day, totalRealized.Month, totalRealized.Lifetime)
+	msg += fmt.Sprintf("\n*Total Portfolio Value:* %.2f USDT. \n*Current:* %.2f USDT. \n*Unrealized PNL:* %.2f USDT (%.2f%%)", totalUSDT, totalCurrentUSDT, totalProfitLoss, totalChange)
+	msg += fmt.Sprintf("\n*Realized PNL:* today %.2f, month %.2f, lifetime %.2f USDT", totalRealized.Day, totalRealized.Month, totalRealized.Lifetime)
+	if err := t.notifier.Send(msg); err != nil {
+		log.Printf("Telegram send error: %v\n", err)
+	} else {
+		log.Println("Telegram summary message sent.")
+	}
+}
+
+// HandleExecutionReport notifies Telegram about fills reported by the user data stream
+func (t *Trader) HandleExecutionReport(r binance.ExecutionReport) {
+	if r.ExecutionType != "TRADE" {
+		return
+	}
+
+	status := "Partially filled"
+	if r.Status == "FILLED" {
+		status = "Filled"
+	}
+	log.Printf("[%s] %s %s %.8f @ %.8f (avg %.8f, %.8f/%.8f)\n",
+		r.Symbol, status, r.Side, r.LastQty, r.LastPrice, r.AverageFillPrice, r.CumulativeQty, r.Quantity)
+
+	msg := fmt.Sprintf("✅ *%s %s #%s* \nFilled: %.8f / %.8f \nAverage Fill Price: %.8f \nTotal: %.8f",
+		status, r.Side, r.Symbol, r.CumulativeQty, r.Quantity, r.AverageFillPrice, r.CumulativeQuote)
+	if r.Commission > 0 {
+		msg += fmt.Sprintf(" \nFee: %.8f %s", r.Commission, r.CommissionAsset)
+	}
+	if err := t.notifier.Send(msg); err != nil {
+		log.Printf("Telegram send error: %v\n", err)
+	}
+}
@@ -0,0 +1,497 @@
+package trader
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"main.go/binance"
+)
+
+// fakeExchange is an in-memory binance.Exchange that records orders
+type fakeExchange struct {
+	balances []binance.AccountBalance
+	open     []*binance.OrderResult
+	ledgers  map[string]*binance.LotLedger // by asset, including assets no longer held
+	prices   map[string]float64
+	dayHigh  float64
+	closes   []float64 // kline closes; a flat, noisy series when nil
+
+	orders    []binance.OrderRequest
+	validated []binance.OrderRequest
+	ocos      []binance.OCORequest
+	ocoErr    error
+	canceled  []int64
+	legFilled float64 // ExecutedQty of a bracket leg when its list is canceled
+}
+
+var errNotFaked = errors.New("not faked")
+
+func (f *fakeExchange) GetAccountBalancesContext(ctx context.Context) ([]binance.AccountBalance, error) {
+	return f.balances, nil
+}
+
+func (f *fakeExchange) GetPricesContext(ctx context.Context, symbols []string) (map[string]float64, error) {
+	return f.prices, nil
+}
+
+// GetKlinesContext returns closes, or a flat, slightly noisy series so the prediction holds
+func (f *fakeExchange) GetKlinesContext(ctx context.Context, symbol, interval string, limit int) ([]binance.Kline, error) {
+	if interval == "1d" {
+		return []binance.Kline{{High: f.dayHigh, Low: f.dayHigh / 2}}, nil
+	}
+	if f.closes != nil {
+		klines := make([]binance.Kline, len(f.closes))
+		for i, c := range f.closes {
+			klines[i].Close = c
+		}
+		return klines, nil
+	}
+	klines := make([]binance.Kline, limit)
+	for i := range klines {
+		klines[i].Close = 100 + math.Sin(float64(i))
+	}
+	return klines, nil
+}
+
+func (f *fakeExchange) GetLotLedgerContext(ctx context.Context, asset string) (*binance.LotLedger, error) {
+	if l, ok := f.ledgers[asset]; ok {
+		return l, nil
+	}
+	return nil, errNotFaked
+}
+
+func (f *fakeExchange) GetTradedAssetsContext(ctx context.Context) ([]string, error) {
+	assets := make([]string, 0, len(f.ledgers))
+	for asset := range f.ledgers {
+		assets = append(assets, asset)
+	}
+	sort.Strings(assets)
+	return assets, nil
+}
+
+func (f *fakeExchange) CreateOrderContext(ctx context.Context, req binance.OrderRequest) (*binance.OrderResult, error) {
+	f.orders = append(f.orders, req)
+	price := f.prices[req.Symbol]
+	qty := req.Quantity
+	if req.QuoteOrderQty > 0 {
+		qty = req.QuoteOrderQty / price
+	}
+	return &binance.OrderResult{
+		Symbol:              req.Symbol,
+		ClientOrderID:       req.ClientOrderID,
+		Side:                req.Side,
+		Type:                req.Type,
+		Status:              "FILLED",
+		ExecutedQty:         qty,
+		CummulativeQuoteQty: qty * price,
+	}, nil
+}
+
+func (f *fakeExchange) TestOrderContext(ctx context.Context, req binance.OrderRequest) (*binance.OrderCommission, error) {
+	f.validated = append(f.validated, req)
+	return &binance.OrderCommission{Taker: 0.001}, nil
+}
+
+func (f *fakeExchange) GetOpenOrdersContext(ctx context.Context, symbol string) ([]*binance.OrderResult, error) {
+	return f.open, nil
+}
+
+func (f *fakeExchange) GetOrderByClientIDContext(ctx context.Context, symbol, clientOrderID string) (*binance.OrderResult, error) {
+	return nil, binance.ErrNoSuchOrder
+}
+
+func (f *fakeExchange) CancelOrderContext(ctx context.Context, symbol string, orderID int64) (*binance.OrderResult, error) {
+	f.canceled = append(f.canceled, orderID)
+	return &binance.OrderResult{Symbol: symbol, OrderID: orderID, Status: "CANCELED"}, nil
+}
+
+func (f *fakeExchange) PlaceOCOContext(ctx context.Context, req binance.OCORequest) (*binance.OrderList, error) {
+	if f.ocoErr != nil {
+		return nil, f.ocoErr
+	}
+	f.ocos = append(f.ocos, req)
+	return &binance.OrderList{OrderListID: int64(len(f.ocos)), Symbol: req.Symbol}, nil
+}
+
+func (f *fakeExchange) GetOrderListContext(ctx context.Context, orderListID int64) (*binance.OrderList, error) {
+	return &binance.OrderList{OrderListID: orderListID, ListOrderStatus: binance.ListOrderStatusExecuting}, nil
+}
+
+func (f *fakeExchange) CancelOrderListContext(ctx context.Context, symbol string, orderListID int64) (*binance.OrderList, error) {
+	f.canceled = append(f.canceled, orderListID)
+	return &binance.OrderList{OrderListID: orderListID, Symbol: symbol, Orders: []*binance.OrderResult{
+		{Symbol: symbol, Side: "SELL", Status: "CANCELED", ExecutedQty: f.legFilled},
+		{Symbol: symbol, Side: "SELL", Status: "CANCELED"},
+	}}, nil
+}
+
+// fakeNotifier collects the messages the trader sends
+type fakeNotifier struct {
+	messages []string
+}
+
+func (n *fakeNotifier) Send(message string) error {
+	n.messages = append(n.messages, message)
+	return nil
+}
+
+func testBalance(free float64) binance.AccountBalance {
+	return binance.AccountBalance{
+		Symbol:       "ABCUSDT",
+		Asset:        "ABC",
+		Free:         free,
+		Total:        free,
+		AveragePrice: 100,
+		CostPrice:    100,
+		TotalUSDT:    free * 100,
+		LedgerQty:    free,
+	}
+}
+
+func TestAutoTrade(t *testing.T) {
+	tests := []struct {
+		name          string
+		price         float64
+		dayHigh       float64
+		dryValidate   bool
+		wantOrders    int
+		wantValidated int
+		wantMsg       string
+	}{
+		{name: "quiet band holds", price: 105, dayHigh: 110},
+		{name: "take profit at day high", price: 120, dayHigh: 119, wantOrders: 1, wantMsg: "Partial Take-Profit: Sold"},
+		{name: "take profit below day high holds", price: 120, dayHigh: 125, wantMsg: "Signal: *HOLD*"},
+		{name: "dry validate places nothing", price: 120, dayHigh: 119, dryValidate: true, wantValidated: 1, wantMsg: "validated"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ex := &fakeExchange{prices: map[string]float64{"ABCUSDT": tt.price}, dayHigh: tt.dayHigh}
+			cfg := DefaultConfig()
+			cfg.DryValidate = tt.dryValidate
+			tr := NewTrader(ex, &fakeNotifier{}, cfg)
+
+			msg := tr.autoTrade(context.Background(), testBalance(20), tt.price)
+
+			if len(ex.orders) != tt.wantOrders {
+				t.Errorf("orders = %d, want %d", len(ex.orders), tt.wantOrders)
+			}
+			if len(ex.validated) != tt.wantValidated {
+				t.Errorf("validated = %d, want %d", len(ex.validated), tt.wantValidated)
+			}
+			for _, req := range append(ex.orders, ex.validated...) {
+				if req.Side != "SELL" || req.Quantity != cfg.MinQuantity || !strings.HasPrefix(req.ClientOrderID, clientOrderPrefix) {
+					t.Errorf("unexpected order %+v", req)
+				}
+			}
+			if tt.wantMsg == "" && msg != "" {
+				t.Errorf("message = %q, want none", msg)
+			}
+			if !strings.Contains(msg, tt.wantMsg) {
+				t.Errorf("message = %q, want it to contain %q", msg, tt.wantMsg)
+			}
+		})
+	}
+}
+
+// buySignalCloses is a long slide, a 20% rally over 14 candles and a 21%
+// drop on the last one, which utils.PredictNextPrice reads as BUY
+func buySignalCloses() []float64 {
+	closes := make([]float64, 0, 200)
+	p := 200.0
+	for len(closes) < 185 {
+		p *= 0.995
+		closes = append(closes, p)
+	}
+	for i := 0; i < 14; i++ {
+		p *= 1 + 0.20/14
+		closes = append(closes, p)
+	}
+	return append(closes, p*0.79)
+}
+
+func TestAutoTradeBuy(t *testing.T) {
+	tests := []struct {
+		name           string
+		buyQuoteAmount float64
+		wantQuoteQty   float64
+		wantQty        float64
+	}{
+		{name: "spends the quote amount", buyQuoteAmount: 25, wantQuoteQty: 25},
+		{name: "buys MinQuantity without a quote amount", buyQuoteAmount: 0, wantQty: 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ex := &fakeExchange{prices: map[string]float64{"ABCUSDT": 80}, dayHigh: 110, closes: buySignalCloses()}
+			cfg := DefaultConfig()
+			cfg.BuyQuoteAmount = tt.buyQuoteAmount
+			tr := NewTrader(ex, &fakeNotifier{}, cfg)
+
+			msg := tr.autoTrade(context.Background(), testBalance(20), 80)
+
+			if len(ex.orders) != 1 {
+				t.Fatalf("orders = %+v, want one buy (message %q)", ex.orders, msg)
+			}
+			req := ex.orders[0]
+			if req.Side != "BUY" || req.Type != binance.OrderTypeMarket || req.QuoteOrderQty != tt.wantQuoteQty || req.Quantity != tt.wantQty {
+				t.Errorf("order = %+v, want BUY with quoteOrderQty %v and quantity %v", req, tt.wantQuoteQty, tt.wantQty)
+			}
+			if !strings.Contains(msg, "DCA Buy Order: Bought") {
+				t.Errorf("message = %q, want the DCA buy", msg)
+			}
+		})
+	}
+}
+
+func TestCronJobNotifies(t *testing.T) {
+	ex := &fakeExchange{
+		balances: []binance.AccountBalance{testBalance(20)},
+		prices:   map[string]float64{"ABCUSDT": 120},
+		dayHigh:  119,
+	}
+	n := &fakeNotifier{}
+	NewTrader(ex, n, DefaultConfig()).CronJob(context.Background())
+
+	if len(n.messages) != 1 || !strings.Contains(n.messages[0], "#ABCUSDT") {
+		t.Fatalf("messages = %q, want one alert for ABCUSDT", n.messages)
+	}
+	if len(ex.orders) != 1 {
+		t.Fatalf("orders = %d, want 1", len(ex.orders))
+	}
+}
+
+func TestHandlePriceTriggersOnCrossing(t *testing.T) {
+	tr := NewTrader(&fakeExchange{}, &fakeNotifier{}, DefaultConfig())
+	tr.watchBalances([]binance.AccountBalance{testBalance(20)})
+
+	queued := func() bool {
+		select {
+		case <-tr.EarlyChecks():
+			return true
+		default:
+			return false
+		}
+	}
+
+	steps := []struct {
+		price float64
+		want  bool
+	}{
+		{105, false}, // inside the band
+		{89, true},   // crossed below
+		{85, false},  // still outside, left to the regular cycle
+		{100, false}, // back inside
+		{111, true},  // crossed above
+	}
+	for _, step := range steps {
+		tr.HandlePrice("ABCUSDT", step.price)
+		if got := queued(); got != step.want {
+			t.Errorf("price %.0f: early check = %t, want %t", step.price, got, step.want)
+		}
+	}
+
+	tr.HandlePrice("XYZUSDT", 1)
+	if queued() {
+		t.Error("early check for an untracked symbol")
+	}
+}
+
+func TestFreshBalanceUsesNewerStreamedPosition(t *testing.T) {
+	tr := NewTrader(&fakeExchange{}, &fakeNotifier{}, DefaultConfig())
+	snapshotAt := time.Now()
+
+	tr.HandleAccountPosition([]binance.AssetPosition{{Asset: "ABC", Free: 3, UpdatedAt: snapshotAt.Add(-time.Second)}})
+	if b := tr.freshBalance(testBalance(20), snapshotAt); b.Free != 20 {
+		t.Errorf("older position applied: Free = %v", b.Free)
+	}
+
+	tr.HandleAccountPosition([]binance.AssetPosition{{Asset: "ABC", Free: 3, Locked: 2, UpdatedAt: snapshotAt.Add(time.Second)}})
+	b := tr.freshBalance(testBalance(20), snapshotAt)
+	if b.Free != 3 || b.Total != 5 || b.TotalUSDT != 500 {
+		t.Errorf("newer position not applied: %+v", b)
+	}
+}
+
+func TestMaintainBracketReportsProblemsOnce(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.BracketTakeProfit = 5
+	cfg.BracketStopLoss = 5
+	ctx := context.Background()
+
+	t.Run("price past the stop", func(t *testing.T) {
+		ex := &fakeExchange{}
+		tr := NewTrader(ex, &fakeNotifier{}, cfg)
+
+		for i, want := range []bool{true, false} {
+			msg := tr.maintainBracket(ctx, testBalance(20), 93)
+			if (msg != "") != want {
+				t.Errorf("cycle %d: message = %q, want reported %t", i, msg, want)
+			}
+		}
+		if len(ex.ocos) != 0 {
+			t.Fatalf("placed %d OCOs below the stop", len(ex.ocos))
+		}
+
+		if msg := tr.maintainBracket(ctx, testBalance(20), 100); !strings.Contains(msg, "OCO bracket for #ABCUSDT") || len(ex.ocos) != 1 {
+			t.Fatalf("bracket not placed back in range: %q", msg)
+		}
+	})
+
+	t.Run("rejected by Binance", func(t *testing.T) {
+		ex := &fakeExchange{ocoErr: &binance.APIError{Code: -2010, Msg: "Order would immediately trigger."}}
+		tr := NewTrader(ex, &fakeNotifier{}, cfg)
+
+		first := tr.maintainBracket(ctx, testBalance(20), 100)
+		if !strings.Contains(first, "would immediately trigger") || strings.Contains(first, "insufficient") {
+			t.Errorf("first rejection = %q", first)
+		}
+		if again := tr.maintainBracket(ctx, testBalance(20), 100); again != "" {
+			t.Errorf("rejection reported again: %q", again)
+		}
+	})
+}
+
+func TestMaintainBracketReplacesOnlyUnfilledQuantity(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.BracketTakeProfit = 5
+	cfg.BracketStopLoss = 5
+	ctx := context.Background()
+	ex := &fakeExchange{}
+	tr := NewTrader(ex, &fakeNotifier{}, cfg)
+
+	tr.maintainBracket(ctx, testBalance(20), 100)
+
+	// the average moved after a buy of 3 while 4 of the bracket sold
+	ex.legFilled = 4
+	balance := testBalance(3)
+	balance.AveragePrice = 98
+	tr.maintainBracket(ctx, balance, 100)
+
+	if len(ex.ocos) != 2 || len(ex.canceled) != 1 {
+		t.Fatalf("ocos = %d, canceled = %v, want the bracket replaced once", len(ex.ocos), ex.canceled)
+	}
+	if got := ex.ocos[1].Quantity; got != 19 {
+		t.Errorf("replacement quantity = %v, want 19 (3 free + 20 bracketed - 4 filled)", got)
+	}
+}
+
+func TestAutoTradeSellsOnlyReleasedQuantity(t *testing.T) {
+	tests := []struct {
+		name      string
+		legFilled float64
+		wantQty   float64 // 0: no sell
+	}{
+		{"bracket released in full", 0, 5},
+		{"leg partly filled", 18, 2},
+		{"leg filled", 20, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.BracketTakeProfit = 50
+			cfg.BracketStopLoss = 50
+			ex := &fakeExchange{prices: map[string]float64{"ABCUSDT": 120}, dayHigh: 119}
+			tr := NewTrader(ex, &fakeNotifier{}, cfg)
+			tr.maintainBracket(context.Background(), testBalance(20), 100)
+
+			ex.legFilled = tt.legFilled
+			tr.autoTrade(context.Background(), testBalance(0), 120)
+
+			if tt.wantQty == 0 {
+				if len(ex.orders) != 0 {
+					t.Fatalf("orders = %+v, want none", ex.orders)
+				}
+				return
+			}
+			if len(ex.orders) != 1 || ex.orders[0].Quantity != tt.wantQty {
+				t.Fatalf("orders = %+v, want one sell of %v", ex.orders, tt.wantQty)
+			}
+		})
+	}
+}
+
+func TestReconcileOrdersDryValidateCancelsNothing(t *testing.T) {
+	open := []*binance.OrderResult{
+		{Symbol: "ABCUSDT", OrderID: 1, OrderListID: 7, ClientOrderID: clientOrderPrefix + "tp-ABCUSDT-x", Side: "SELL", TransactTime: time.Now()},
+		{Symbol: "ABCUSDT", OrderID: 2, OrderListID: -1, ClientOrderID: clientOrderPrefix + "buy-ABCUSDT-x", Side: "BUY", TransactTime: time.Now().Add(-48 * time.Hour)},
+	}
+	for _, dry := range []bool{false, true} {
+		ex := &fakeExchange{open: open}
+		cfg := DefaultConfig()
+		cfg.DryValidate = dry
+		NewTrader(ex, &fakeNotifier{}, cfg).reconcileOrders(context.Background())
+
+		want := 2
+		if dry {
+			want = 0
+		}
+		if len(ex.canceled) != want {
+			t.Errorf("DryValidate %t: canceled %v, want %d cancellations", dry, ex.canceled, want)
+		}
+	}
+}
+
+func TestNewClientOrderIDLongSymbol(t *testing.T) {
+	for _, kind := range []string{"buy", "sell", "oco", "tp", "sl"} {
+		id := newClientOrderID(kind, "1000SATSFDUSDBTCUSDTETH")
+		if len(id) > binance.MaxClientOrderIDLen {
+			t.Errorf("%s: %q is %d characters, want at most %d", kind, id, len(id), binance.MaxClientOrderIDLen)
+		}
+		if !isOwnOrder(&binance.OrderResult{ClientOrderID: id}) {
+			t.Errorf("%s: %q not recognized as the bot's order", kind, id)
+		}
+	}
+}
+
+func TestReportLots(t *testing.T) {
+	balance := testBalance(2)
+	balance.OpenLots = []binance.Lot{{Time: time.Now(), Qty: 2, Price: 100}}
+	balance.Sales = []binance.Sale{{Time: time.Now(), Qty: 1, Proceeds: 150, CostBasis: 100, RealizedPnL: 50}}
+	balance.RealizedPnL = 50
+
+	n := &fakeNotifier{}
+	NewTrader(&fakeExchange{balances: []binance.AccountBalance{balance}}, n, DefaultConfig()).ReportLots(context.Background())
+
+	if len(n.messages) != 1 {
+		t.Fatalf("messages = %q, want one report", n.messages)
+	}
+	for _, want := range []string{"[#ABC] realized: 50.00", "2.00000000 @ 100.00000000", "PnL +50.00"} {
+		if !strings.Contains(n.messages[0], want) {
+			t.Errorf("report %q does not contain %q", n.messages[0], want)
+		}
+	}
+}
+
+func TestSummarizeBalancesRealizedPnL(t *testing.T) {
+	now := time.Now()
+	lastYear := now.AddDate(-1, 0, 0)
+
+	held := testBalance(20)
+	held.Sales = []binance.Sale{{Time: now, RealizedPnL: 10}, {Time: lastYear, RealizedPnL: 5}}
+	closed := &binance.LotLedger{Asset: "OLD", Sales: []binance.Sale{{Time: lastYear, RealizedPnL: -3}}}
+
+	ex := &fakeExchange{
+		balances: []binance.AccountBalance{held},
+		prices:   map[string]float64{"ABCUSDT": 110},
+		ledgers:  map[string]*binance.LotLedger{"ABC": {Asset: "ABC"}, "OLD": closed},
+	}
+	n := &fakeNotifier{}
+	NewTrader(ex, n, DefaultConfig()).SummarizeBalances(context.Background())
+
+	if len(n.messages) != 1 {
+		t.Fatalf("messages = %q, want one summary", n.messages)
+	}
+	for _, want := range []string{
+		"[#OLD]: closed - Realized: -3.00",
+		"*Unrealized PNL:* 200.00 USDT",
+		"*Realized PNL:* today 10.00, month 10.00, lifetime 12.00 USDT",
+	} {
+		if !strings.Contains(n.messages[0], want) {
+			t.Errorf("summary %q does not contain %q", n.messages[0], want)
+		}
+	}
+}